@@ -7,6 +7,7 @@ import (
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/markbates/goth/gothic"
+	"github.com/svenrisse/bookshelf/internal/auth"
 	"github.com/svenrisse/bookshelf/internal/models"
 )
 
@@ -26,6 +27,7 @@ func (app *application) AuthCallbackFunction(w http.ResponseWriter, r *http.Requ
 	id, err := strconv.Atoi(user.UserID)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
+		return
 	}
 
 	exists, err := app.models.Users.Exists(id)
@@ -45,6 +47,18 @@ func (app *application) AuthCallbackFunction(w http.ResponseWriter, r *http.Requ
 			app.serverErrorResponse(w, r, err)
 			return
 		}
+
+		err = app.models.Permissions.AddForUser(int64(id), models.DefaultPermissions...)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	err = auth.SetSessionUser(w, r, id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
 	}
 
 	http.Redirect(w, r, "https://bookshelf.svenrisse.com/v1/healthcheck", http.StatusTemporaryRedirect)
@@ -66,6 +80,13 @@ func (app *application) AuthLogout(w http.ResponseWriter, r *http.Request) {
 	ctx := context.WithValue(context.Background(), "provider", provider)
 	r = r.WithContext(ctx)
 	gothic.Logout(w, r)
+
+	err := auth.ClearSession(w, r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
 	w.Header().Set("Location", "/")
 	w.WriteHeader(http.StatusTemporaryRedirect)
 }
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/svenrisse/bookshelf/internal/models"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+// createAuthorHandler godoc
+//
+//	@Summary	Create an Author
+//	@Tags		authors
+//	@Accept		json
+//	@Produce	json
+//	@Param		author	body		models.Author	true	"Add author"
+//	@Success	201		{object}	models.Author
+//	@Failure	400
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/authors [post]
+func (app *application) createAuthorHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name string `json:"name"`
+		Bio  string `json:"bio"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	author := &models.Author{
+		Name: input.Name,
+		Bio:  input.Bio,
+	}
+
+	v := validator.New()
+	if models.ValidateAuthor(v, author); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Authors.Insert(author)
+	if err != nil {
+		if errors.Is(err, models.ErrDuplicateAuthor) {
+			v.AddError("name", "an author with this name already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/authors/%d", author.ID))
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"author": author}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// listAuthorsHandler godoc
+//
+//	@Summary	List Authors
+//	@Tags		authors
+//	@Produce	json
+//	@Param		name		query		string	false	"Part of the name"
+//	@Param		page		query		int		false	"Page"
+//	@Param		page_size	query		int		false	"Page size"
+//	@Param		sort		query		string	false	"name, id, -name or -id"
+//	@Success	200			{array}		models.Author
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/authors [get]
+func (app *application) listAuthorsHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name string
+		models.Filters
+	}
+
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	input.Name = app.readString(qs, "name", "")
+	input.Filters.Page = app.readInt(qs, "page", 1, v)
+	input.PageSize = app.readInt(qs, "page_size", 20, v)
+	input.Sort = app.readString(qs, "sort", "name")
+	input.SortSafeList = []string{"id", "name", "-id", "-name"}
+
+	if models.ValidateFilters(v, input.Filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	authors, metadata, err := app.models.Authors.List(input.Name, input.Filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"authors": authors, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// getAuthorHandler godoc
+//
+//	@Summary	Get an Author
+//	@Tags		authors
+//	@Produce	json
+//	@Param		id	path		int	true	"Author ID"
+//	@Success	200	{object}	models.Author
+//	@Failure	404
+//	@Failure	500
+//	@Router		/v1/authors/{id} [get]
+func (app *application) getAuthorHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	author, err := app.models.Authors.Get(id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"author": author}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// updateAuthorHandler godoc
+//
+//	@Summary	Update an Author by providing new values
+//	@Tags		authors
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		int				true	"Author ID"
+//	@Param		author	body		models.Author	true	"Provide Fields to change"
+//	@Success	200		{object}	models.Author
+//	@Failure	400
+//	@Failure	404
+//	@Failure	409
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/authors/{id} [patch]
+func (app *application) updateAuthorHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	author, err := app.models.Authors.Get(id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	var input struct {
+		Name *string `json:"name"`
+		Bio  *string `json:"bio"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.Name != nil {
+		author.Name = *input.Name
+	}
+	if input.Bio != nil {
+		author.Bio = *input.Bio
+	}
+
+	v := validator.New()
+	if models.ValidateAuthor(v, author); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Authors.Update(author)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		case errors.Is(err, models.ErrDuplicateAuthor):
+			v.AddError("name", "an author with this name already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"author": author}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// deleteAuthorHandler godoc
+//
+//	@Summary		Delete an Author
+//	@Description	removes the author's links to books; the bylines of those books stay as they are
+//	@Tags			authors
+//	@Produce		json
+//	@Param			id	path	int	true	"Author ID"
+//	@Success		200
+//	@Failure		403
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/authors/{id} [delete]
+func (app *application) deleteAuthorHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Authors.Delete(id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "author successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// listAuthorBooksHandler godoc
+//
+//	@Summary	List the books an Author contributed to
+//	@Tags		authors
+//	@Produce	json
+//	@Param		id			path		int		true	"Author ID"
+//	@Param		page		query		int		false	"Page"
+//	@Param		page_size	query		int		false	"Page size"
+//	@Param		sort		query		string	false	"year, title, -year or -title"
+//	@Success	200			{array}		models.AuthoredBook
+//	@Failure	404
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/authors/{id}/books [get]
+func (app *application) listAuthorBooksHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		models.Filters
+	}
+
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	input.Filters.Page = app.readInt(qs, "page", 1, v)
+	input.PageSize = app.readInt(qs, "page_size", 20, v)
+	input.Sort = app.readString(qs, "sort", "year")
+	input.SortSafeList = []string{"year", "title", "-year", "-title"}
+
+	if models.ValidateFilters(v, input.Filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	author, err := app.models.Authors.Get(id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	books, metadata, err := app.models.Authors.ListBooks(author.ID, input.Filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"author": author, "books": books, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
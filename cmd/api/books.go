@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/svenrisse/bookshelf/internal/models"
 	"github.com/svenrisse/bookshelf/internal/validator"
@@ -17,8 +18,10 @@ import (
 //	@Accept			json
 //	@Produce		json
 //	@Param			book	body		models.Book	true	"Add book"
+//	@Param			force	query		bool		false	"Create the book even if it looks like a duplicate"
 //	@Success		201		{object}	models.Book
 //	@Failure		400
+//	@Failure		409
 //	@Failure		422
 //	@Failure		500
 //	@Router			/v1/books [post]
@@ -30,13 +33,59 @@ func (app *application) createBookHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	book.ISBN = models.NormalizeISBN(book.ISBN)
+
 	v := validator.New()
-	if models.ValidateBook(v, book); !v.Valid() {
+
+	force := app.readBool(r.URL.Query(), "force", v)
+
+	genres, err := app.models.Genres.Vocabulary()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if models.ValidateBook(v, book, genres); !v.Valid() {
 		app.failedValidationResponse(w, r, v.Errors)
 		return
 	}
 
+	if !app.checkBookAuthors(w, r, v, book.Authors) {
+		return
+	}
+
+	if force == nil || !*force {
+		duplicates, err := app.models.Books.FindDuplicates(book)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		if len(duplicates) > 0 {
+			app.duplicateBooksResponse(w, r, duplicates)
+			return
+		}
+	}
+
 	err = app.models.Books.Insert(book)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrDuplicateISBN):
+			v.AddError("isbn", "a book with this ISBN already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+		case errors.Is(err, models.ErrWorkNotFound):
+			v.AddError("work_id", "must be an existing work")
+			app.failedValidationResponse(w, r, v.Errors)
+		case errors.Is(err, models.ErrAuthorNotFound):
+			v.AddError("authors", "must only contain existing authors")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	book.Authors, err = app.models.Authors.ListForBook(book.ID)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
@@ -53,16 +102,19 @@ func (app *application) createBookHandler(w http.ResponseWriter, r *http.Request
 
 // getBookHandler godoc
 //
-//	@Summary	Get a Book
-//	@Tags		books
-//	@Accept		json
-//	@Produce	json
-//	@Param		id	path		int	true	"Book ID"
-//	@Success	200	{object}	models.Book
-//	@Failure	400
-//	@Failure	404
-//	@Failure	500
-//	@Router		/v1/books/{id} [get]
+//	@Summary		Get a Book
+//	@Description	the ETag changes with anything in the body, including the editions and ratings
+//	@Tags			books
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		int		true	"Book ID"
+//	@Param			If-None-Match	header		string	false	"ETag of the copy the client has"
+//	@Success		200				{object}	models.Book
+//	@Success		304
+//	@Failure		400
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/books/{id} [get]
 func (app *application) getBookHandler(w http.ResponseWriter, r *http.Request) {
 	id, err := app.readIDParam(r)
 	if err != nil {
@@ -80,10 +132,95 @@ func (app *application) getBookHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, envelope{"book": book}, nil)
+	book.Authors, err = app.models.Authors.ListForBook(book.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.writeBook(w, r, book)
+}
+
+// bookRepresentation returns the body sent for a single book, with the other
+// editions of its work and their ratings, and its ETag. Every handler that
+// returns a single book builds it here, so that the tag of a book is the same
+// whichever of them sent it.
+func (app *application) bookRepresentation(book *models.Book) (envelope, string, error) {
+	editions, err := app.models.Works.Editions(book.WorkID, book.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ratings, err := app.models.Reviews.RatingStatsForWork(book.WorkID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	env := envelope{"book": book, "editions": editions, "ratings": ratings}
+
+	etag, err := contentETag(book.Version, env)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return env, etag, nil
+}
+
+// writeBook sends a book, or 304 Not Modified when the client already has it.
+func (app *application) writeBook(w http.ResponseWriter, r *http.Request, book *models.Book) {
+	env, etag, err := app.bookRepresentation(book)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if app.notModified(w, r, etag) {
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// getBookByISBNHandler godoc
+//
+//	@Summary		Get a Book by its ISBN
+//	@Description	returns the same body and ETag as getting the book by its ID
+//	@Tags			books
+//	@Produce		json
+//	@Param			isbn			path		string	true	"ISBN-10 or ISBN-13"
+//	@Param			If-None-Match	header		string	false	"ETag of the copy the client has"
+//	@Success		200				{object}	models.Book
+//	@Success		304
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/books/isbn/{isbn} [get]
+func (app *application) getBookByISBNHandler(w http.ResponseWriter, r *http.Request) {
+	isbn := models.NormalizeISBN(r.PathValue("isbn"))
+	if !models.ValidISBN13(isbn) {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	book, err := app.models.Books.GetByISBN(isbn)
 	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
 		app.serverErrorResponse(w, r, err)
+		return
 	}
+
+	book.Authors, err = app.models.Authors.ListForBook(book.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.writeBook(w, r, book)
 }
 
 // updateBookHandler godoc
@@ -92,12 +229,14 @@ func (app *application) getBookHandler(w http.ResponseWriter, r *http.Request) {
 //	@Tags		books
 //	@Accept		json
 //	@Produce	json
-//	@Param		id		path		int			true	"Book ID"
-//	@Param		book	body		models.Book	true	"Provide Fields to change"
-//	@Success	200		{object}	models.Book
+//	@Param		id			path		int			true	"Book ID"
+//	@Param		If-Match	header		string		false	"ETag of the version the changes are based on"
+//	@Param		book		body		models.Book	true	"Provide Fields to change"
+//	@Success	200			{object}	models.Book
 //	@Failure	400
 //	@Failure	404
 //	@Failure	409
+//	@Failure	412
 //	@Failure	422
 //	@Failure	500
 //	@Router		/v1/books/{id} [patch]
@@ -118,12 +257,23 @@ func (app *application) updateBookHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if !app.checkIfMatch(w, r, book.Version) {
+		return
+	}
+
 	var input struct {
-		Title  *string  `json:"title"`
-		Author *string  `json:"author"`
-		Year   *int32   `json:"year"`
-		Pages  *int32   `json:"pages"`
-		Genres []string `json:"genres"`
+		Title         *string             `json:"title"`
+		OriginalTitle *string             `json:"original_title"`
+		Author        *string             `json:"author"`
+		Year          *int32              `json:"year"`
+		Pages         *int32              `json:"pages"`
+		Genres        []string            `json:"genres"`
+		ISBN          *string             `json:"isbn"`
+		Publisher     *string             `json:"publisher"`
+		Language      *string             `json:"language"`
+		Description   *string             `json:"description"`
+		CoverURL      *string             `json:"cover_url"`
+		Authors       []models.BookAuthor `json:"authors"`
 	}
 
 	err = app.readJSON(w, r, &input)
@@ -135,6 +285,8 @@ func (app *application) updateBookHandler(w http.ResponseWriter, r *http.Request
 	if input.Title != nil {
 		book.Title = *input.Title
 	}
+	bylineChanged := input.Author != nil && *input.Author != book.Author
+
 	if input.Author != nil {
 		book.Author = *input.Author
 	}
@@ -147,29 +299,214 @@ func (app *application) updateBookHandler(w http.ResponseWriter, r *http.Request
 	if input.Genres != nil {
 		book.Genres = *&input.Genres
 	}
+	if input.OriginalTitle != nil {
+		book.OriginalTitle = *input.OriginalTitle
+	}
+	if input.ISBN != nil {
+		book.ISBN = models.NormalizeISBN(*input.ISBN)
+	}
+	if input.Publisher != nil {
+		book.Publisher = *input.Publisher
+	}
+	if input.Language != nil {
+		book.Language = *input.Language
+	}
+	if input.Description != nil {
+		book.Description = *input.Description
+	}
+	if input.CoverURL != nil {
+		book.CoverURL = *input.CoverURL
+	}
+	if input.Authors != nil {
+		book.Authors = input.Authors
+	}
+
+	genres, err := app.models.Genres.Vocabulary()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
 
 	v := validator.New()
-	if models.ValidateBook(v, book); !v.Valid() {
+	if models.ValidateBook(v, book, genres); !v.Valid() {
 		app.failedValidationResponse(w, r, v.Errors)
 		return
 	}
 
+	if !app.checkBookAuthors(w, r, v, input.Authors) {
+		return
+	}
+
 	err = app.models.Books.Update(book)
 	if err != nil {
-		if errors.Is(err, models.ErrEditConflict) {
+		switch {
+		case errors.Is(err, models.ErrEditConflict):
 			app.editConflictResponse(w, r)
+		case errors.Is(err, models.ErrDuplicateISBN):
+			v.AddError("isbn", "a book with this ISBN already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	if input.Authors != nil || bylineChanged {
+		err = app.saveBookAuthors(book, input.Authors)
+	} else {
+		book.Authors, err = app.models.Authors.ListForBook(book.ID)
+	}
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	env, etag, err := app.bookRepresentation(book)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("ETag", etag)
+
+	err = app.writeJSON(w, http.StatusOK, env, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// checkBookAuthors reports whether all the given contributors exist, and
+// sends a failed validation response if they don't.
+func (app *application) checkBookAuthors(
+	w http.ResponseWriter,
+	r *http.Request,
+	v *validator.Validator,
+	authors []models.BookAuthor,
+) bool {
+	if len(authors) == 0 {
+		return true
+	}
+
+	exist, err := app.models.Authors.Exist(authors)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return false
+	}
+
+	if !exist {
+		v.AddError("authors", "must only contain existing authors")
+		app.failedValidationResponse(w, r, v.Errors)
+		return false
+	}
+
+	return true
+}
+
+// saveBookAuthors links the book to the given contributors or, when there are
+// none, to the names in its byline, and reloads book.Authors.
+func (app *application) saveBookAuthors(book *models.Book, authors []models.BookAuthor) error {
+	var err error
+
+	if len(authors) > 0 {
+		err = app.models.Authors.SetForBook(book.ID, authors)
+	} else {
+		err = app.models.Authors.LinkByline(book.ID, book.Author)
+	}
+	if err != nil {
+		return err
+	}
+
+	book.Authors, err = app.models.Authors.ListForBook(book.ID)
+	return err
+}
+
+// mergeBookHandler godoc
+//
+//	@Summary		Merge a duplicate into a Book
+//	@Description	moves the shelf entries, reviews, authors and series of duplicate_id to the book and deletes the duplicate
+//	@Tags			books
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Book ID"
+//	@Success		200	{object}	models.Book
+//	@Failure		400
+//	@Failure		403
+//	@Failure		404
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/books/{id}/merge [post]
+func (app *application) mergeBookHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		DuplicateID int64 `json:"duplicate_id"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+
+	v.Check(input.DuplicateID > 0, "duplicate_id", "must be provided")
+	v.Check(input.DuplicateID != id, "duplicate_id", "must not be the book itself")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Books.Merge(id, input.DuplicateID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
 			return
 		}
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
+	book, err := app.models.Books.Get(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	book.Authors, err = app.models.Authors.ListForBook(book.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
 	err = app.writeJSON(w, http.StatusOK, envelope{"book": book}, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
 }
 
+// deleteBookHandler godoc
+//
+//	@Summary		Delete a Book
+//	@Description	soft-deletes the book; refused with 409 while users have it on their shelf, unless force=true
+//	@Tags			books
+//	@Produce		json
+//	@Param			id			path	int		true	"Book ID"
+//	@Param			force		query	bool	false	"Delete the book even if users have it on their shelf"
+//	@Param			If-Match	header	string	false	"ETag of the version the client means to delete"
+//	@Success		200
+//	@Failure		404
+//	@Failure		409
+//	@Failure		412
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/books/{id} [delete]
 func (app *application) deleteBookHandler(w http.ResponseWriter, r *http.Request) {
 	id, err := app.readIDParam(r)
 	if err != nil {
@@ -177,6 +514,42 @@ func (app *application) deleteBookHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	v := validator.New()
+
+	force := app.readBool(r.URL.Query(), "force", v)
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	book, err := app.models.Books.Get(id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if !app.checkIfMatch(w, r, book.Version) {
+		return
+	}
+
+	if force == nil || !*force {
+		users, err := app.models.Books.CountUsers(id)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		if users > 0 {
+			app.bookInUseResponse(w, r, users)
+			return
+		}
+	}
+
 	err = app.models.Books.Delete(id)
 	if err != nil {
 		if errors.Is(err, models.ErrRecordNotFound) {
@@ -193,10 +566,128 @@ func (app *application) deleteBookHandler(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// restoreBookHandler godoc
+//
+//	@Summary	Restore a deleted Book
+//	@Tags		books
+//	@Produce	json
+//	@Param		id	path		int	true	"Book ID"
+//	@Success	200	{object}	models.Book
+//	@Failure	403
+//	@Failure	404
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/books/{id}/restore [post]
+func (app *application) restoreBookHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Books.Restore(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		case errors.Is(err, models.ErrDuplicateISBN):
+			v := validator.New()
+			v.AddError("isbn", "another book with this ISBN exists, merge this book into it instead")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	book, err := app.models.Books.Get(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	book.Authors, err = app.models.Authors.ListForBook(book.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"book": book}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// suggestBooksHandler godoc
+//
+//	@Summary	Suggest titles and authors while typing
+//	@Tags		books
+//	@Produce	json
+//	@Param		q		query		string	true	"What has been typed so far"
+//	@Param		limit	query		int		false	"Number of suggestions, at most 20"
+//	@Success	200		{array}		models.Suggestion
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/books/suggest [get]
+func (app *application) suggestBooksHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	q := strings.TrimSpace(app.readString(qs, "q", ""))
+	limit := app.readInt(qs, "limit", 8, v)
+
+	v.Check(q != "", "q", "must be provided")
+	v.Check(len(q) <= 100, "q", "must not be more than 100 bytes long")
+	v.Check(limit > 0, "limit", "must be greater than zero")
+	v.Check(limit <= 20, "limit", "must be a maximum of 20")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	suggestions, err := app.models.Books.Suggest(q, limit)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"suggestions": suggestions}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// listBooksHandler godoc
+//
+//	@Summary	List Books
+//	@Tags		books
+//	@Produce	json
+//	@Param		title		query		string	false	"Words of the title"
+//	@Param		q			query		string	false	"Search title, author, genres and description, matching word prefixes"
+//	@Param		author		query		string	false	"Name of one of the authors"
+//	@Param		language	query		string	false	"ISO 639 language code"
+//	@Param		genres		query		string	false	"Comma separated genres, all of which the book has"
+//	@Param		genres_any	query		string	false	"Comma separated genres, one of which the book has"
+//	@Param		genres_not	query		string	false	"Comma separated genres the book doesn't have"
+//	@Param		subgenres	query		bool	false	"Let the genre filters match subgenres too"
+//	@Param		year_min	query		int		false	"Earliest year"
+//	@Param		year_max	query		int		false	"Latest year"
+//	@Param		pages_min	query		int		false	"Least pages"
+//	@Param		pages_max	query		int		false	"Most pages"
+//	@Param		rating_min	query		number	false	"Lowest average rating"
+//	@Param		page		query		int		false	"Page"
+//	@Param		page_size	query		int		false	"Page size"
+//	@Param		cursor		query		string	false	"next_cursor of the previous page, instead of page"
+//	@Param		sort		query		string	false	"id, title, author, year, pages or relevance; prefix with - to sort descending"
+//	@Success	200			{array}		models.Book
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/books [get]
 func (app *application) listBooksHandler(w http.ResponseWriter, r *http.Request) {
 	var input struct {
-		Title  string
-		Genres []string
+		models.BookFilters
 		models.Filters
 	}
 
@@ -205,15 +696,38 @@ func (app *application) listBooksHandler(w http.ResponseWriter, r *http.Request)
 	qs := r.URL.Query()
 
 	input.Title = app.readString(qs, "title", "")
+	input.Search = app.readString(qs, "q", "")
+	input.Author = app.readString(qs, "author", "")
+	input.Language = app.readString(qs, "language", "")
 	input.Genres = app.readCSV(qs, "genres", []string{})
+	input.GenresAny = app.readCSV(qs, "genres_any", []string{})
+	input.GenresNot = app.readCSV(qs, "genres_not", []string{})
+	input.YearMin = int32(app.readInt(qs, "year_min", 0, v))
+	input.YearMax = int32(app.readInt(qs, "year_max", 0, v))
+	input.PagesMin = int32(app.readInt(qs, "pages_min", 0, v))
+	input.PagesMax = int32(app.readInt(qs, "pages_max", 0, v))
+	input.RatingMin = app.readFloat(qs, "rating_min", 0, v)
+	if subgenres := app.readBool(qs, "subgenres", v); subgenres != nil {
+		input.Subgenres = *subgenres
+	}
+
 	input.Filters.Page = app.readInt(qs, "page", 1, v)
 	input.PageSize = app.readInt(qs, "page_size", 20, v)
+	input.Cursor = app.readString(qs, "cursor", "")
+
+	defaultSort := "id"
+	if input.Search != "" {
+		defaultSort = "relevance"
+	}
+
+	input.Sort = app.readString(qs, "sort", defaultSort)
 	input.SortSafeList = []string{
 		"id",
 		"title",
 		"author",
 		"year",
 		"pages",
+		"relevance",
 		"-id",
 		"-title",
 		"-author",
@@ -221,12 +735,15 @@ func (app *application) listBooksHandler(w http.ResponseWriter, r *http.Request)
 		"-pages",
 	}
 
+	v.Check(input.Sort != "relevance" || input.Search != "", "sort", "relevance needs a search query q")
+
+	models.ValidateBookFilters(v, input.BookFilters)
 	if models.ValidateFilters(v, input.Filters); !v.Valid() {
 		app.failedValidationResponse(w, r, v.Errors)
 		return
 	}
 
-	books, metadata, err := app.models.Books.ListBooks(input.Title, input.Genres, input.Filters)
+	books, metadata, err := app.models.Books.ListBooks(input.BookFilters, input.Filters)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
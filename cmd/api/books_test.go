@@ -116,12 +116,52 @@ func TestValidateBookHandler(t *testing.T) {
 				},
 			}, wantError: map[string]string{"genres": "must not contain more than 10 genres"},
 		},
+		{
+			name: "invalid isbn", book: models.Book{
+				Title:  validBook.Title,
+				Author: validBook.Author,
+				Year:   validBook.Year,
+				Pages:  validBook.Pages,
+				Genres: validBook.Genres,
+				ISBN:   "9780261103574",
+			}, wantError: map[string]string{"isbn": "must be a valid ISBN-10 or ISBN-13"},
+		},
+		{
+			name: "invalid language", book: models.Book{
+				Title:    validBook.Title,
+				Author:   validBook.Author,
+				Year:     validBook.Year,
+				Pages:    validBook.Pages,
+				Genres:   validBook.Genres,
+				Language: "English",
+			}, wantError: map[string]string{"language": "must be a lowercase ISO 639 language code"},
+		},
+		{
+			name: "negative work id", book: models.Book{
+				Title:  validBook.Title,
+				Author: validBook.Author,
+				Year:   validBook.Year,
+				Pages:  validBook.Pages,
+				Genres: validBook.Genres,
+				WorkID: -1,
+			}, wantError: map[string]string{"work_id": "must be a positive integer"},
+		},
+		{
+			name: "invalid cover url", book: models.Book{
+				Title:    validBook.Title,
+				Author:   validBook.Author,
+				Year:     validBook.Year,
+				Pages:    validBook.Pages,
+				Genres:   validBook.Genres,
+				CoverURL: "javascript:alert(1)",
+			}, wantError: map[string]string{"cover_url": "must be an http or https URL"},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			v := validator.New()
-			models.ValidateBook(v, &tt.book)
+			models.ValidateBook(v, &tt.book, nil)
 			assert.DeepEqual(t, tt.wantError, v.Errors)
 		})
 	}
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"net/http"
+
+	"github.com/svenrisse/bookshelf/internal/models"
 )
 
 func (app *application) logError(r *http.Request, err error) {
@@ -54,6 +56,31 @@ func (app *application) editConflictResponse(w http.ResponseWriter, r *http.Requ
 	app.errorResponse(w, r, http.StatusConflict, message)
 }
 
+func (app *application) preconditionFailedResponse(w http.ResponseWriter, r *http.Request) {
+	message := "the resource has changed since you fetched it, fetch it again before changing it"
+	app.errorResponse(w, r, http.StatusPreconditionFailed, message)
+}
+
+func (app *application) duplicateBooksResponse(w http.ResponseWriter, r *http.Request, duplicates []*models.Edition) {
+	message := "this book looks like a duplicate, create it with force=true if it is not"
+
+	err := app.writeJSON(w, http.StatusConflict, envelope{"error": message, "duplicates": duplicates}, nil)
+	if err != nil {
+		app.logError(r, err)
+		w.WriteHeader(500)
+	}
+}
+
+func (app *application) bookInUseResponse(w http.ResponseWriter, r *http.Request, users int) {
+	message := fmt.Sprintf("this book is on the shelf of %d users, delete it with force=true to hide it from the catalog anyway", users)
+
+	err := app.writeJSON(w, http.StatusConflict, envelope{"error": message, "affected_users": users}, nil)
+	if err != nil {
+		app.logError(r, err)
+		w.WriteHeader(500)
+	}
+}
+
 func (app *application) rateLimitExceededResponse(w http.ResponseWriter, r *http.Request) {
 	message := "rate limit exceeded"
 	app.errorResponse(w, r, http.StatusTooManyRequests, message)
@@ -85,3 +112,8 @@ func (app *application) notPermittedResponse(w http.ResponseWriter, r *http.Requ
 	message := "your user account doesn't have the necessary permissions to access this resource"
 	app.errorResponse(w, r, http.StatusForbidden, message)
 }
+
+func (app *application) sessionRequiredResponse(w http.ResponseWriter, r *http.Request) {
+	message := "this action requires a browser session and can't be performed with an authentication token"
+	app.errorResponse(w, r, http.StatusForbidden, message)
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/svenrisse/bookshelf/internal/goodreads"
+	"github.com/svenrisse/bookshelf/internal/models"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+// exportWriteWindow is how long the client gets to accept each batch of rows.
+// The deadline is pushed back after every batch, so an export is only cut off
+// when the client stops reading, not because the library is large.
+const (
+	exportWriteWindow = 10 * time.Second
+	exportBatchSize   = 100
+)
+
+// exportWriter writes a single export format. flush hands buffered rows on to
+// the response, end closes the document.
+type exportWriter interface {
+	begin() error
+	write(entry *models.ExportEntry) error
+	flush() error
+	end() error
+}
+
+// getExportHandler godoc
+//
+//	@Summary		Export the library of the current User
+//	@Description	Streams every shelved book with its book fields, review, shelves and read dates.
+//	@Tags			export
+//	@Produce		json
+//	@Produce		text/csv
+//	@Param			format	query	string	false	"csv (default), json or goodreads"
+//	@Success		200
+//	@Failure		401
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/user/export [get]
+func (app *application) getExportHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	format := app.readString(r.URL.Query(), "format", "csv")
+
+	v.Check(validator.PermittedValue(format, "csv", "json", "goodreads"), "format", "must be csv, json or goodreads")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	var ew exportWriter
+	switch format {
+	case "json":
+		ew = &jsonExport{w: w}
+	case "goodreads":
+		ew = &goodreadsExport{w: goodreads.NewWriter(w)}
+	default:
+		ew = &csvExport{w: csv.NewWriter(w)}
+	}
+
+	rc := http.NewResponseController(w)
+
+	// extendDeadline pushes the write deadline back. Writers that don't
+	// support deadlines, like the recorder in tests, have no deadline to
+	// extend.
+	extendDeadline := func() error {
+		err := rc.SetWriteDeadline(time.Now().Add(exportWriteWindow))
+		if errors.Is(err, http.ErrNotSupported) {
+			return nil
+		}
+		return err
+	}
+
+	start := func() error {
+		contentType, filename := "text/csv; charset=utf-8", "bookshelf-"+format+".csv"
+		if format == "json" {
+			contentType, filename = "application/json", "bookshelf.json"
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+		return ew.begin()
+	}
+
+	err := extendDeadline()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	user := app.contextGetUser(r)
+	rows := 0
+
+	err = app.models.Export.ForUser(r.Context(), int64(user.ID), func(entry *models.ExportEntry) error {
+		if rows == 0 {
+			err := start()
+			if err != nil {
+				return err
+			}
+		}
+
+		rows++
+
+		err := ew.write(entry)
+		if err != nil {
+			return err
+		}
+
+		if rows%exportBatchSize == 0 {
+			err = ew.flush()
+			if err != nil {
+				return err
+			}
+
+			return extendDeadline()
+		}
+
+		return nil
+	})
+	if err != nil {
+		// Once the first row is out the status line has been sent, so all
+		// that is left to do is to log the error and cut the response short.
+		if rows == 0 {
+			app.serverErrorResponse(w, r, err)
+		} else {
+			app.logError(r, err)
+		}
+		return
+	}
+
+	if rows == 0 {
+		err = start()
+		if err != nil {
+			app.logError(r, err)
+			return
+		}
+	}
+
+	err = ew.end()
+	if err != nil {
+		app.logError(r, err)
+	}
+}
+
+type csvExport struct {
+	w *csv.Writer
+}
+
+func (e *csvExport) begin() error {
+	return e.w.Write([]string{
+		"book_id", "title", "author", "year", "pages", "genres", "isbn", "status", "rating",
+		"review_body", "added_at", "read_at", "reviewed_at", "shelves", "read_dates",
+	})
+}
+
+func (e *csvExport) write(entry *models.ExportEntry) error {
+	return e.w.Write([]string{
+		strconv.FormatInt(entry.BookID, 10),
+		entry.Title,
+		entry.Author,
+		strconv.Itoa(int(entry.Year)),
+		strconv.Itoa(int(entry.Pages)),
+		strings.Join(entry.Genres, ";"),
+		entry.ISBN,
+		entry.Status,
+		strconv.FormatFloat(float64(entry.Rating), 'f', -1, 32),
+		entry.ReviewBody,
+		entry.AddedAt.Format(time.RFC3339),
+		formatOptionalTime(entry.ReadAt),
+		formatOptionalTime(entry.ReviewedAt),
+		strings.Join(entry.Shelves, ";"),
+		strings.Join(entry.ReadDates, ";"),
+	})
+}
+
+func (e *csvExport) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExport) end() error {
+	return e.flush()
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+// jsonExport writes {"userBooks": [...]} one entry at a time instead of
+// marshalling the whole library like writeJSON would.
+type jsonExport struct {
+	w       http.ResponseWriter
+	written bool
+}
+
+func (e *jsonExport) begin() error {
+	_, err := io.WriteString(e.w, `{"userBooks":[`)
+	return err
+}
+
+func (e *jsonExport) write(entry *models.ExportEntry) error {
+	js, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if e.written {
+		js = append([]byte{','}, js...)
+	}
+	e.written = true
+
+	_, err = e.w.Write(js)
+	return err
+}
+
+func (e *jsonExport) flush() error {
+	return http.NewResponseController(e.w).Flush()
+}
+
+func (e *jsonExport) end() error {
+	_, err := io.WriteString(e.w, "]}\n")
+	return err
+}
+
+type goodreadsExport struct {
+	w *goodreads.Writer
+}
+
+func (e *goodreadsExport) begin() error {
+	return e.w.WriteHeader()
+}
+
+func (e *goodreadsExport) write(entry *models.ExportEntry) error {
+	record := goodreads.Record{
+		Title:     entry.Title,
+		Author:    entry.Author,
+		ISBN:      entry.ISBN,
+		Rating:    int(math.Round(float64(entry.Rating))),
+		Review:    entry.ReviewBody,
+		Pages:     entry.Pages,
+		Year:      entry.Year,
+		DateAdded: entry.AddedAt,
+		Shelves:   entry.Shelves,
+		ReadCount: len(entry.ReadDates),
+	}
+
+	if entry.ReadAt != nil {
+		record.DateRead = *entry.ReadAt
+	}
+
+	switch entry.Status {
+	case models.StatusRead:
+		record.ExclusiveShelf = goodreads.ShelfRead
+	case models.StatusReading:
+		record.ExclusiveShelf = goodreads.ShelfCurrentlyReading
+	case models.StatusWantToRead:
+		record.ExclusiveShelf = goodreads.ShelfToRead
+	default:
+		record.ExclusiveShelf = entry.Status
+	}
+
+	return e.w.Write(record)
+}
+
+func (e *goodreadsExport) flush() error {
+	return e.w.Flush()
+}
+
+func (e *goodreadsExport) end() error {
+	return e.flush()
+}
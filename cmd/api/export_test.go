@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+	"github.com/svenrisse/bookshelf/internal/goodreads"
+	"github.com/svenrisse/bookshelf/internal/models"
+)
+
+var exportEntries = []*models.ExportEntry{
+	{
+		BookID:  1,
+		Title:   "The Hobbit",
+		Author:  "J.R.R. Tolkien",
+		Year:    1937,
+		Pages:   310,
+		Genres:  []string{"Fantasy"},
+		Status:  models.StatusRead,
+		Rating:  4.5,
+		AddedAt: time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC),
+		Shelves: []string{"favorites"},
+	},
+	{
+		BookID:  2,
+		Title:   "A Game of Thrones",
+		Author:  "George R.R. Martin",
+		Year:    1996,
+		Pages:   694,
+		Genres:  []string{"Fantasy", "Epic"},
+		Status:  models.StatusWantToRead,
+		AddedAt: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+	},
+}
+
+func writeExport(t *testing.T, ew exportWriter) {
+	assert.NilError(t, ew.begin())
+	for _, entry := range exportEntries {
+		assert.NilError(t, ew.write(entry))
+	}
+	assert.NilError(t, ew.end())
+}
+
+func TestJSONExport(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	writeExport(t, &jsonExport{w: w})
+
+	var got struct {
+		UserBooks []models.ExportEntry `json:"userBooks"`
+	}
+
+	err := json.Unmarshal(w.Body.Bytes(), &got)
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(got.UserBooks), 2)
+	assert.Equal(t, got.UserBooks[1].Title, "A Game of Thrones")
+}
+
+func TestGoodreadsExport(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	writeExport(t, &goodreadsExport{w: goodreads.NewWriter(w)})
+
+	records, err := goodreads.Read(strings.NewReader(w.Body.String()))
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(records), 2)
+	assert.Equal(t, records[0].Rating, 5)
+	assert.Equal(t, records[0].ExclusiveShelf, goodreads.ShelfRead)
+	assert.Equal(t, records[0].Shelves[0], "favorites")
+	assert.Equal(t, records[1].ExclusiveShelf, goodreads.ShelfToRead)
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/svenrisse/bookshelf/internal/models"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+// listGenresHandler godoc
+//
+//	@Summary	List the Genres as a tree with their book counts
+//	@Tags		genres
+//	@Produce	json
+//	@Success	200	{array}	models.Genre
+//	@Failure	500
+//	@Router		/v1/genres [get]
+func (app *application) listGenresHandler(w http.ResponseWriter, r *http.Request) {
+	genres, err := app.models.Genres.Tree()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"genres": genres}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// getGenreHandler godoc
+//
+//	@Summary	Get a Genre
+//	@Tags		genres
+//	@Produce	json
+//	@Param		id	path		int	true	"Genre ID"
+//	@Success	200	{object}	models.Genre
+//	@Failure	404
+//	@Failure	500
+//	@Router		/v1/genres/{id} [get]
+func (app *application) getGenreHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	genre, err := app.models.Genres.Get(id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"genre": genre}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// createGenreHandler godoc
+//
+//	@Summary	Create a Genre
+//	@Tags		genres
+//	@Accept		json
+//	@Produce	json
+//	@Param		genre	body		models.Genre	true	"Add genre"
+//	@Success	201		{object}	models.Genre
+//	@Failure	400
+//	@Failure	403
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/genres [post]
+func (app *application) createGenreHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name     string   `json:"name"`
+		ParentID int64    `json:"parent_id"`
+		Aliases  []string `json:"aliases"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	genre := &models.Genre{
+		Name:     input.Name,
+		ParentID: input.ParentID,
+		Aliases:  input.Aliases,
+	}
+
+	v := validator.New()
+	if models.ValidateGenre(v, genre); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Genres.Insert(genre)
+	if err != nil {
+		app.genreErrorResponse(w, r, v, err)
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/genres/%d", genre.ID))
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"genre": genre}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// updateGenreHandler godoc
+//
+//	@Summary		Update a Genre
+//	@Description	renaming a genre renames it in all books; aliases replace the existing ones
+//	@Tags			genres
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Genre ID"
+//	@Success		200	{object}	models.Genre
+//	@Failure		400
+//	@Failure		403
+//	@Failure		404
+//	@Failure		409
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/genres/{id} [patch]
+func (app *application) updateGenreHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	genre, err := app.models.Genres.Get(id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	var input struct {
+		Name     *string  `json:"name"`
+		ParentID *int64   `json:"parent_id"`
+		Aliases  []string `json:"aliases"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.Name != nil {
+		genre.Name = *input.Name
+	}
+	if input.ParentID != nil {
+		genre.ParentID = *input.ParentID
+	}
+	if input.Aliases != nil {
+		genre.Aliases = input.Aliases
+	}
+
+	v := validator.New()
+	if models.ValidateGenre(v, genre); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Genres.Update(genre)
+	if err != nil {
+		app.genreErrorResponse(w, r, v, err)
+		return
+	}
+
+	genre, err = app.models.Genres.Get(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"genre": genre}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// mergeGenreHandler godoc
+//
+//	@Summary		Merge a duplicate into a Genre
+//	@Description	books with duplicate_id get the genre instead, the duplicate's name and aliases become aliases of the genre and its subgenres move below the genre
+//	@Tags			genres
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Genre ID"
+//	@Success		200	{object}	models.Genre
+//	@Failure		400
+//	@Failure		403
+//	@Failure		404
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/genres/{id}/merge [post]
+func (app *application) mergeGenreHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		DuplicateID int64 `json:"duplicate_id"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+
+	v.Check(input.DuplicateID > 0, "duplicate_id", "must be provided")
+	v.Check(input.DuplicateID != id, "duplicate_id", "must not be the genre itself")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Genres.Merge(id, input.DuplicateID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	genre, err := app.models.Genres.Get(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"genre": genre}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// genreErrorResponse reports an error from saving a genre.
+func (app *application) genreErrorResponse(w http.ResponseWriter, r *http.Request, v *validator.Validator, err error) {
+	switch {
+	case errors.Is(err, models.ErrEditConflict):
+		app.editConflictResponse(w, r)
+	case errors.Is(err, models.ErrDuplicateGenre):
+		v.AddError("name", "a genre with this name or alias already exists")
+		app.failedValidationResponse(w, r, v.Errors)
+	case errors.Is(err, models.ErrGenreNotFound):
+		v.AddError("parent_id", "must be an existing genre")
+		app.failedValidationResponse(w, r, v.Errors)
+	case errors.Is(err, models.ErrGenreCycle):
+		v.AddError("parent_id", "must not be one of the genre's subgenres")
+		app.failedValidationResponse(w, r, v.Errors)
+	default:
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/svenrisse/bookshelf/internal/models"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+// createGoalHandler godoc
+//
+//	@Summary		Set a reading Goal for a year
+//	@Description	year defaults to the current year
+//	@Tags			goals
+//	@Accept			json
+//	@Produce		json
+//	@Success		201	{object}	models.Goal
+//	@Failure		400
+//	@Failure		401
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/user/goals [post]
+func (app *application) createGoalHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Year        int `json:"year"`
+		TargetBooks int `json:"target_books"`
+		TargetPages int `json:"target_pages"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.Year == 0 {
+		input.Year = time.Now().Year()
+	}
+
+	user := app.contextGetUser(r)
+
+	goal := &models.Goal{
+		UserID:      int64(user.ID),
+		Year:        input.Year,
+		TargetBooks: input.TargetBooks,
+		TargetPages: input.TargetPages,
+	}
+
+	v := validator.New()
+	if models.ValidateGoal(v, goal); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Goals.Insert(goal)
+	if err != nil {
+		if errors.Is(err, models.ErrDuplicateGoal) {
+			v.AddError("year", "a goal for this year already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/user/goals/%d", goal.Year))
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"goal": goal}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// listGoalsHandler godoc
+//
+//	@Summary	List the reading Goals of the current User with their progress
+//	@Tags		goals
+//	@Produce	json
+//	@Success	200	{array}	models.Goal
+//	@Failure	401
+//	@Failure	500
+//	@Router		/v1/user/goals [get]
+func (app *application) listGoalsHandler(w http.ResponseWriter, r *http.Request) {
+	user := app.contextGetUser(r)
+
+	goals, err := app.models.Goals.ListForUser(int64(user.ID))
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"goals": goals}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// getGoalHandler godoc
+//
+//	@Summary	Get the reading Goal of a year with its progress
+//	@Tags		goals
+//	@Produce	json
+//	@Param		year	path		int	true	"Year"
+//	@Success	200		{object}	models.Goal
+//	@Failure	401
+//	@Failure	404
+//	@Failure	500
+//	@Router		/v1/user/goals/{year} [get]
+func (app *application) getGoalHandler(w http.ResponseWriter, r *http.Request) {
+	year, err := app.readYearParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	goal, err := app.models.Goals.GetForUser(int64(user.ID), year)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"goal": goal}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// updateGoalHandler godoc
+//
+//	@Summary	Update the reading Goal of a year
+//	@Tags		goals
+//	@Accept		json
+//	@Produce	json
+//	@Param		year	path		int	true	"Year"
+//	@Success	200		{object}	models.Goal
+//	@Failure	400
+//	@Failure	401
+//	@Failure	404
+//	@Failure	409
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/user/goals/{year} [patch]
+func (app *application) updateGoalHandler(w http.ResponseWriter, r *http.Request) {
+	year, err := app.readYearParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	goal, err := app.models.Goals.GetForUser(int64(user.ID), year)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	var input struct {
+		TargetBooks *int `json:"target_books"`
+		TargetPages *int `json:"target_pages"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.TargetBooks != nil {
+		goal.TargetBooks = *input.TargetBooks
+	}
+	if input.TargetPages != nil {
+		goal.TargetPages = *input.TargetPages
+	}
+
+	v := validator.New()
+	if models.ValidateGoal(v, goal); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Goals.Update(goal)
+	if err != nil {
+		if errors.Is(err, models.ErrEditConflict) {
+			app.editConflictResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"goal": goal}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// deleteGoalHandler godoc
+//
+//	@Summary	Delete the reading Goal of a year
+//	@Tags		goals
+//	@Produce	json
+//	@Param		year	path	int	true	"Year"
+//	@Success	200
+//	@Failure	401
+//	@Failure	404
+//	@Failure	500
+//	@Router		/v1/user/goals/{year} [delete]
+func (app *application) deleteGoalHandler(w http.ResponseWriter, r *http.Request) {
+	year, err := app.readYearParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	err = app.models.Goals.DeleteForUser(int64(user.ID), year)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "goal successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
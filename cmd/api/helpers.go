@@ -1,6 +1,7 @@
 package main
 
 import (
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/svenrisse/bookshelf/internal/validator"
@@ -27,6 +29,28 @@ func (app *application) readIDParam(r *http.Request) (int64, error) {
 	return id, nil
 }
 
+func (app *application) readBookIDParam(r *http.Request) (int64, error) {
+	params := httprouter.ParamsFromContext(r.Context())
+
+	id, err := strconv.ParseInt(params.ByName("bookid"), 10, 64)
+	if err != nil || id < 1 {
+		return 0, errors.New("invalid bookid parameter")
+	}
+
+	return id, nil
+}
+
+func (app *application) readYearParam(r *http.Request) (int, error) {
+	params := httprouter.ParamsFromContext(r.Context())
+
+	year, err := strconv.Atoi(params.ByName("year"))
+	if err != nil || year < 1 {
+		return 0, errors.New("invalid year parameter")
+	}
+
+	return year, nil
+}
+
 func (app *application) writeJSON(
 	w http.ResponseWriter,
 	status int,
@@ -144,6 +168,59 @@ func (app *application) readInt(
 	return i
 }
 
+func (app *application) readFloat(
+	qs url.Values,
+	key string,
+	defaultValue float32,
+	v *validator.Validator,
+) float32 {
+	s := qs.Get(key)
+
+	if s == "" {
+		return defaultValue
+	}
+
+	f, err := strconv.ParseFloat(s, 32)
+	if err != nil {
+		v.AddError(key, "must be a number")
+		return defaultValue
+	}
+
+	return float32(f)
+}
+
+func (app *application) readBool(qs url.Values, key string, v *validator.Validator) *bool {
+	s := qs.Get(key)
+
+	if s == "" {
+		return nil
+	}
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		v.AddError(key, "must be a boolean value")
+		return nil
+	}
+
+	return &b
+}
+
+func (app *application) readDate(qs url.Values, key string, v *validator.Validator) time.Time {
+	s := qs.Get(key)
+
+	if s == "" {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		v.AddError(key, "must be a date in the format YYYY-MM-DD")
+		return time.Time{}
+	}
+
+	return t
+}
+
 func (app *application) readCSV(qs url.Values, key string, defaultValue []string) []string {
 	csv := qs.Get(key)
 
@@ -168,3 +245,80 @@ func (app *application) background(fn func()) {
 		fn()
 	}()
 }
+
+// contentETag is the entity tag of a response body holding a resource at the
+// given version. The body may also carry data that changes without the
+// version, like the ratings of a book, so the tag is the version followed by a
+// hash of the whole body.
+func contentETag(version int32, data envelope) (string, error) {
+	js, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(js)
+
+	return fmt.Sprintf(`"%d-%x"`, version, sum[:8]), nil
+}
+
+// etagVersion returns the version an entity tag made by contentETag stands
+// for, or "" for a weak tag.
+func etagVersion(etag string) string {
+	if !strings.HasPrefix(etag, `"`) {
+		return ""
+	}
+
+	version, _, _ := strings.Cut(strings.Trim(etag, `"`), "-")
+	return version
+}
+
+// etagList splits If-Match or If-None-Match header values into their entity
+// tags.
+func etagList(values []string) []string {
+	var etags []string
+
+	for _, value := range values {
+		for _, etag := range strings.Split(value, ",") {
+			etags = append(etags, strings.TrimSpace(etag))
+		}
+	}
+
+	return etags
+}
+
+// notModified sets the ETag of the response. When the client already has a
+// body with that tag, as told by If-None-Match, it answers with 304 Not
+// Modified and returns true. A W/ prefix is ignored, as If-None-Match asks for.
+func (app *application) notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+
+	for _, candidate := range etagList(r.Header.Values("If-None-Match")) {
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkIfMatch answers with 412 Precondition Failed and returns false when the
+// request has an If-Match header that doesn't name the given version of the
+// resource. Only the version part of the tags is compared, so a change to the
+// rest of a body doesn't keep the client from changing the resource. Requests
+// without If-Match always pass.
+func (app *application) checkIfMatch(w http.ResponseWriter, r *http.Request, version int32) bool {
+	values := r.Header.Values("If-Match")
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, candidate := range etagList(values) {
+		if candidate == "*" || etagVersion(candidate) == strconv.Itoa(int(version)) {
+			return true
+		}
+	}
+
+	app.preconditionFailedResponse(w, r)
+	return false
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+)
+
+func TestContentETag(t *testing.T) {
+	etag, err := contentETag(3, envelope{"book": "The Hobbit", "ratings": 4.5})
+	assert.NilError(t, err)
+	assert.Equal(t, etagVersion(etag), "3")
+
+	same, err := contentETag(3, envelope{"book": "The Hobbit", "ratings": 4.5})
+	assert.NilError(t, err)
+	assert.Equal(t, same, etag)
+
+	rerated, err := contentETag(3, envelope{"book": "The Hobbit", "ratings": 4})
+	assert.NilError(t, err)
+	assert.Equal(t, rerated == etag, false)
+	assert.Equal(t, etagVersion(rerated), "3")
+}
+
+func TestNotModified(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{name: "No header", ifNoneMatch: "", want: false},
+		{name: "Current body", ifNoneMatch: `"3-0a1b"`, want: true},
+		{name: "Weak current body", ifNoneMatch: `W/"3-0a1b"`, want: true},
+		{name: "One of several", ifNoneMatch: `"2-9f8e", "3-0a1b"`, want: true},
+		{name: "Same version, other body", ifNoneMatch: `"3-9f8e"`, want: false},
+		{name: "Any body", ifNoneMatch: "*", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApplication(t)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/v1/books/1", nil)
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			assert.Equal(t, app.notModified(w, r, `"3-0a1b"`), tt.want)
+			assert.Equal(t, w.Header().Get("ETag"), `"3-0a1b"`)
+
+			if tt.want {
+				assert.Equal(t, w.Code, http.StatusNotModified)
+			}
+		})
+	}
+}
+
+func TestCheckIfMatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		ifMatch    string
+		want       bool
+		wantStatus int
+	}{
+		{name: "No header", ifMatch: "", want: true, wantStatus: http.StatusOK},
+		{name: "Current version", ifMatch: `"3-0a1b"`, want: true, wantStatus: http.StatusOK},
+		{name: "Current version, other body", ifMatch: `"3-9f8e"`, want: true, wantStatus: http.StatusOK},
+		{name: "Any version", ifMatch: "*", want: true, wantStatus: http.StatusOK},
+		{name: "Old version", ifMatch: `"2-0a1b"`, want: false, wantStatus: http.StatusPreconditionFailed},
+		{name: "Weak tag", ifMatch: `W/"3-0a1b"`, want: false, wantStatus: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApplication(t)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPatch, "/v1/books/1", nil)
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			assert.Equal(t, app.checkIfMatch(w, r, 3), tt.want)
+			assert.Equal(t, w.Code, tt.wantStatus)
+		})
+	}
+}
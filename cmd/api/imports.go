@@ -0,0 +1,366 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/svenrisse/bookshelf/internal/goodreads"
+	"github.com/svenrisse/bookshelf/internal/models"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+const maxImportBytes = 10 << 20
+
+// importGenre is given to books created from an import, since Goodreads
+// exports carry no genres and books need at least one.
+const importGenre = "Uncategorized"
+
+// createImportHandler godoc
+//
+//	@Summary		Import a Goodreads library export
+//	@Description	Upload the CSV from Goodreads as the "file" field of a multipart form. The rows are imported in the background; poll the returned import for progress. Uploading the same file again returns the existing import, or runs it again if it failed or was abandoned.
+//	@Tags			imports
+//	@Accept			mpfd
+//	@Produce		json
+//	@Param			file	formData	file	true	"Goodreads CSV export"
+//	@Success		200		{object}	models.Import
+//	@Success		202		{object}	models.Import
+//	@Failure		400
+//	@Failure		401
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/user/imports [post]
+func (app *application) createImportHandler(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			app.badRequestResponse(w, r, fmt.Errorf("body must not be larger than %d bytes", maxBytesError.Limit))
+			return
+		}
+		app.badRequestResponse(w, r, errors.New(`body must be a multipart form with the export in the "file" field`))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	records, err := goodreads.Read(bytes.NewReader(data))
+	if err != nil {
+		app.failedValidationResponse(w, r, map[string]string{"file": err.Error()})
+		return
+	}
+
+	user := app.contextGetUser(r)
+	hash := sha256.Sum256(data)
+
+	imp := &models.Import{
+		UserID:    int64(user.ID),
+		FileHash:  hash[:],
+		Source:    models.ImportSourceGoodreads,
+		TotalRows: len(records),
+	}
+
+	headers := make(http.Header)
+
+	err = app.models.Imports.Insert(imp)
+	if err != nil {
+		if !errors.Is(err, models.ErrDuplicateImport) {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		imp, err = app.models.Imports.GetForUserByHash(int64(user.ID), hash[:])
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		// a failed or abandoned import of the file is run again, any other is
+		// returned as it is
+		err = app.models.Imports.Restart(imp)
+		if err != nil {
+			if !errors.Is(err, models.ErrEditConflict) {
+				app.serverErrorResponse(w, r, err)
+				return
+			}
+
+			headers.Set("Location", fmt.Sprintf("/v1/user/imports/%d", imp.ID))
+
+			err = app.writeJSON(w, http.StatusOK, envelope{"import": imp}, headers)
+			if err != nil {
+				app.serverErrorResponse(w, r, err)
+			}
+			return
+		}
+	}
+
+	job := *imp
+	app.background(func() {
+		app.runGoodreadsImport(&job, records)
+	})
+
+	headers.Set("Location", fmt.Sprintf("/v1/user/imports/%d", imp.ID))
+
+	err = app.writeJSON(w, http.StatusAccepted, envelope{"import": imp}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// getImportHandler godoc
+//
+//	@Summary	Get the status of an import
+//	@Tags		imports
+//	@Produce	json
+//	@Param		id	path		int	true	"Import ID"
+//	@Success	200	{object}	models.Import
+//	@Failure	401
+//	@Failure	404
+//	@Failure	500
+//	@Router		/v1/user/imports/{id} [get]
+func (app *application) getImportHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	imp, err := app.models.Imports.GetForUser(int64(user.ID), id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"import": imp}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// importRowError is a problem with a single row of an import that is reported
+// back to the user, as opposed to an internal error that is only logged.
+type importRowError string
+
+func (e importRowError) Error() string {
+	return string(e)
+}
+
+func validationRowError(errs map[string]string) importRowError {
+	keys := make([]string, 0, len(errs))
+	for key := range errs {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	reasons := make([]string, len(keys))
+	for i, key := range keys {
+		reasons[i] = fmt.Sprintf("%s: %s", key, errs[key])
+	}
+
+	return importRowError(strings.Join(reasons, "; "))
+}
+
+func (app *application) runGoodreadsImport(imp *models.Import, records []goodreads.Record) {
+	// an import that stops before the end, by an error or a panic, is marked
+	// as failed so that the file can be uploaded again
+	defer func() {
+		if imp.Status == models.ImportStatusCompleted {
+			return
+		}
+
+		finishedAt := time.Now()
+		imp.Status = models.ImportStatusFailed
+		imp.FinishedAt = &finishedAt
+
+		err := app.models.Imports.Update(imp)
+		if err != nil {
+			app.logger.Error(err.Error(), "import", imp.ID)
+		}
+	}()
+
+	imp.Status = models.ImportStatusRunning
+
+	err := app.models.Imports.Update(imp)
+	if err != nil {
+		app.logger.Error(err.Error(), "import", imp.ID)
+		return
+	}
+
+	for _, record := range records {
+		created, err := app.importGoodreadsRecord(imp.UserID, record)
+
+		imp.Processed++
+
+		switch {
+		case err != nil:
+			var rowErr importRowError
+			if !errors.As(err, &rowErr) {
+				app.logger.Error(err.Error(), "import", imp.ID, "row", record.Row)
+				rowErr = "the row could not be saved"
+			}
+
+			imp.Failed++
+
+			failure := models.ImportFailure{Row: record.Row, Title: record.Title, Reason: rowErr.Error()}
+
+			err = app.models.Imports.AddFailure(imp.ID, failure)
+			if err != nil {
+				app.logger.Error(err.Error(), "import", imp.ID, "row", record.Row)
+			}
+		case created:
+			imp.Created++
+		default:
+			imp.Matched++
+		}
+
+		err = app.models.Imports.Update(imp)
+		if err != nil {
+			app.logger.Error(err.Error(), "import", imp.ID)
+		}
+	}
+
+	finishedAt := time.Now()
+	imp.Status = models.ImportStatusCompleted
+	imp.FinishedAt = &finishedAt
+
+	err = app.models.Imports.Update(imp)
+	if err != nil {
+		app.logger.Error(err.Error(), "import", imp.ID)
+	}
+}
+
+// importGoodreadsRecord adds a single row of a Goodreads export to the user's
+// library and reports whether the book had to be created. Books are matched
+// by ISBN, or by title and author when the row has no usable ISBN. Rows whose
+// book is already in the library are left as they are, which makes importing
+// the same data twice harmless.
+func (app *application) importGoodreadsRecord(userID int64, record goodreads.Record) (bool, error) {
+	created := false
+
+	isbn := models.NormalizeISBN(record.ISBN)
+	if !models.ValidISBN13(isbn) {
+		isbn = ""
+	}
+
+	book, err := app.models.Books.GetByISBN(isbn)
+	if errors.Is(err, models.ErrRecordNotFound) {
+		book, err = app.models.Books.GetByTitleAuthor(record.Title, record.Author)
+	}
+	if err != nil {
+		if !errors.Is(err, models.ErrRecordNotFound) {
+			return false, err
+		}
+
+		book = &models.Book{
+			Title:  record.Title,
+			Author: record.Author,
+			Year:   record.Year,
+			Pages:  record.Pages,
+			Genres: []string{importGenre},
+			ISBN:   isbn,
+		}
+
+		genres, err := app.models.Genres.Vocabulary()
+		if err != nil {
+			return false, err
+		}
+
+		v := validator.New()
+		if models.ValidateBook(v, book, genres); !v.Valid() {
+			return false, validationRowError(v.Errors)
+		}
+
+		err = app.models.Books.Insert(book)
+		if err != nil {
+			return false, err
+		}
+
+		created = true
+	}
+
+	userBook := &models.UserBook{
+		BookID:     book.ID,
+		UserID:     userID,
+		Rating:     float32(record.Rating),
+		ReviewBody: record.Review,
+		ReadAt:     record.DateRead,
+		CreatedAt:  record.DateAdded,
+	}
+
+	switch record.ExclusiveShelf {
+	case goodreads.ShelfRead:
+		userBook.SetStatus(models.StatusRead)
+	case goodreads.ShelfCurrentlyReading:
+		userBook.SetStatus(models.StatusReading)
+	default:
+		userBook.SetStatus(models.StatusWantToRead)
+	}
+
+	if userBook.ReviewBody != "" {
+		userBook.ReviewedAt = userBook.ReadAt
+		if userBook.ReviewedAt.IsZero() {
+			userBook.ReviewedAt = time.Now()
+		}
+	}
+
+	v := validator.New()
+	if models.ValidateUserBook(v, userBook); !v.Valid() {
+		return created, validationRowError(v.Errors)
+	}
+
+	err = app.models.UserBook.Insert(userBook)
+	if err != nil {
+		if !errors.Is(err, models.ErrDuplicateUserBook) {
+			return created, err
+		}
+
+		userBook, err = app.models.UserBook.GetForUser(userID, book.ID)
+		if err != nil {
+			return created, err
+		}
+	}
+
+	for _, name := range record.Shelves {
+		shelf, err := app.models.Shelves.GetForUserByName(userID, name)
+		if errors.Is(err, models.ErrRecordNotFound) {
+			shelf = &models.Shelf{UserID: userID, Name: name}
+
+			v := validator.New()
+			if models.ValidateShelf(v, shelf); !v.Valid() {
+				return created, validationRowError(v.Errors)
+			}
+
+			err = app.models.Shelves.Insert(shelf)
+		}
+		if err != nil {
+			return created, err
+		}
+
+		_, err = app.models.Shelves.AddBook(shelf.ID, userBook.ID, nil)
+		if err != nil {
+			return created, err
+		}
+	}
+
+	return created, nil
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+)
+
+func TestValidationRowError(t *testing.T) {
+	err := validationRowError(map[string]string{
+		"year":  "must be provided",
+		"pages": "must be provided",
+	})
+
+	assert.Equal(t, err.Error(), "pages: must be provided; year: must be provided")
+}
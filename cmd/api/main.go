@@ -32,9 +32,11 @@ type config struct {
 		ssl          string
 	}
 	limiter struct {
-		rps     float64
-		burst   int
-		enabled bool
+		rps          float64
+		burst        int
+		suggestRPS   float64
+		suggestBurst int
+		enabled      bool
 	}
 	cors struct {
 		trustedOrigins []string
@@ -77,6 +79,8 @@ func main() {
 	flag.BoolVar(&cfg.limiter.enabled, "limiter-enabled", true, "Enable rate limiter")
 	flag.Float64Var(&cfg.limiter.rps, "limiter-rps", 2, "Rate limiter maximum requests per second")
 	flag.IntVar(&cfg.limiter.burst, "limiter-burst", 4, "Rate limiter maximum burst")
+	flag.Float64Var(&cfg.limiter.suggestRPS, "limiter-suggest-rps", 10, "Rate limiter maximum suggestions per second")
+	flag.IntVar(&cfg.limiter.suggestBurst, "limiter-suggest-burst", 20, "Rate limiter maximum burst of suggestions")
 
 	flag.Func(
 		"cors-trusted-origins",
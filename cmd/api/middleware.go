@@ -1,13 +1,18 @@
 package main
 
 import (
+	"errors"
 	"expvar"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/svenrisse/bookshelf/internal/auth"
+	"github.com/svenrisse/bookshelf/internal/models"
+	"github.com/svenrisse/bookshelf/internal/validator"
 	"github.com/tomasen/realip"
 	"golang.org/x/time/rate"
 )
@@ -25,10 +30,14 @@ func (app *application) recoverPanic(next http.Handler) http.Handler {
 	})
 }
 
+// rateLimit limits the requests per client IP. Search suggestions are sent on
+// every keystroke, so they are counted by a separate, more generous limiter
+// and don't use up the budget of the other requests.
 func (app *application) rateLimit(next http.Handler) http.Handler {
 	type client struct {
-		limiter  *rate.Limiter
-		lastSeen time.Time
+		limiter        *rate.Limiter
+		suggestLimiter *rate.Limiter
+		lastSeen       time.Time
 	}
 
 	var (
@@ -57,12 +66,20 @@ func (app *application) rateLimit(next http.Handler) http.Handler {
 			mu.Lock()
 
 			if _, found := clients[ip]; !found {
-				clients[ip] = &client{limiter: rate.NewLimiter(rate.Limit(app.config.limiter.rps), app.config.limiter.burst)}
+				clients[ip] = &client{
+					limiter:        rate.NewLimiter(rate.Limit(app.config.limiter.rps), app.config.limiter.burst),
+					suggestLimiter: rate.NewLimiter(rate.Limit(app.config.limiter.suggestRPS), app.config.limiter.suggestBurst),
+				}
 			}
 
 			clients[ip].lastSeen = time.Now()
 
-			if !clients[ip].limiter.Allow() {
+			limiter := clients[ip].limiter
+			if r.URL.Path == "/v1/books/suggest" {
+				limiter = clients[ip].suggestLimiter
+			}
+
+			if !limiter.Allow() {
 				mu.Unlock()
 				app.rateLimitExceededResponse(w, r)
 				return
@@ -75,6 +92,106 @@ func (app *application) rateLimit(next http.Handler) http.Handler {
 	})
 }
 
+func (app *application) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Authorization")
+		w.Header().Add("Vary", "Cookie")
+
+		authorizationHeader := r.Header.Get("Authorization")
+
+		if authorizationHeader != "" {
+			headerParts := strings.Split(authorizationHeader, " ")
+			if len(headerParts) != 2 || headerParts[0] != "Bearer" {
+				app.invalidAuthenticationTokenResponse(w, r)
+				return
+			}
+
+			token := headerParts[1]
+
+			v := validator.New()
+
+			if models.ValidateTokenPlaintext(v, token); !v.Valid() {
+				app.invalidAuthenticationTokenResponse(w, r)
+				return
+			}
+
+			user, err := app.models.Users.GetForToken(models.ScopeAuthentication, token)
+			if err != nil {
+				switch {
+				case errors.Is(err, models.ErrRecordNotFound):
+					app.invalidAuthenticationTokenResponse(w, r)
+				default:
+					app.serverErrorResponse(w, r, err)
+				}
+				return
+			}
+
+			r = app.contextSetUser(r, user)
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		userID, ok := auth.GetSessionUser(r)
+		if !ok {
+			r = app.contextSetUser(r, models.AnonymousUser)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user, err := app.models.Users.Get(userID)
+		if err != nil {
+			switch {
+			// the session outlived its user, carry on as if it wasn't there
+			case errors.Is(err, models.ErrRecordNotFound):
+				r = app.contextSetUser(r, models.AnonymousUser)
+				next.ServeHTTP(w, r)
+			default:
+				app.serverErrorResponse(w, r, err)
+			}
+			return
+		}
+
+		r = app.contextSetUser(r, user)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (app *application) requireAuthenticatedUser(next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := app.contextGetUser(r)
+
+		if user.IsAnonymous() {
+			app.authenticationRequiredResponse(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (app *application) requirePermission(code string, next http.HandlerFunc) http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		user := app.contextGetUser(r)
+
+		permissions, err := app.models.Permissions.GetAllForUser(int64(user.ID))
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		if !permissions.Include(code) {
+			app.notPermittedResponse(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+
+	return app.requireAuthenticatedUser(fn)
+}
+
 func (app *application) enableCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Add("Vary", "Origin")
@@ -87,12 +204,13 @@ func (app *application) enableCORS(next http.Handler) http.Handler {
 			for i := range app.config.cors.trustedOrigins {
 				if origin == app.config.cors.trustedOrigins[i] {
 					w.Header().Set("Access-Control-Allow-Origin", origin)
+					w.Header().Set("Access-Control-Expose-Headers", "ETag")
 
 					// check for preflight req
 					if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
 
 						w.Header().Set("Access-Control-Allow-Methods", "OPTIONS, PUT, PATCH, DELETE")
-						w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+						w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, If-Match, If-None-Match")
 
 						w.WriteHeader(http.StatusOK)
 						return
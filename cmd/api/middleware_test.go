@@ -0,0 +1,118 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+	"github.com/svenrisse/bookshelf/internal/models"
+)
+
+func TestRequireAuthenticatedUser(t *testing.T) {
+	app := newTestApplication(t)
+
+	next := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	tests := []struct {
+		name     string
+		user     *models.User
+		wantCode int
+	}{
+		{name: "Anonymous user", user: models.AnonymousUser, wantCode: http.StatusUnauthorized},
+		{name: "Authenticated user", user: &models.User{ID: 1, Name: "Alice Jones"}, wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/v1/user/books", nil)
+			req = app.contextSetUser(req, tt.user)
+
+			app.requireAuthenticatedUser(next).ServeHTTP(w, req)
+
+			assert.Equal(t, w.Result().StatusCode, tt.wantCode)
+		})
+	}
+}
+
+func TestAuthenticateMalformedHeader(t *testing.T) {
+	app := newTestApplication(t)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{name: "Missing scheme", header: "Q5KJHXE3TJ3BUQRFWYYCAFSJDQ"},
+		{name: "Wrong scheme", header: "Basic Q5KJHXE3TJ3BUQRFWYYCAFSJDQ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/v1/user/books", nil)
+			req.Header.Set("Authorization", tt.header)
+
+			app.authenticate(next).ServeHTTP(w, req)
+
+			assert.Equal(t, w.Result().StatusCode, http.StatusUnauthorized)
+			assert.Equal(t, w.Result().Header.Get("WWW-Authenticate"), "Bearer")
+		})
+	}
+}
+
+func TestAuthenticateInvalidTokenFormat(t *testing.T) {
+	app := newTestApplication(t)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v1/user/books", nil)
+	req.Header.Set("Authorization", "Bearer tooshort")
+
+	app.authenticate(next).ServeHTTP(w, req)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusUnauthorized)
+}
+
+func TestRateLimitSuggestions(t *testing.T) {
+	app := newTestApplication(t)
+	app.config.limiter.enabled = true
+	app.config.limiter.rps = 2
+	app.config.limiter.burst = 4
+	app.config.limiter.suggestRPS = 10
+	app.config.limiter.suggestBurst = 20
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler := app.rateLimit(next)
+
+	send := func(path string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+
+		handler.ServeHTTP(w, req)
+
+		return w.Result().StatusCode
+	}
+
+	for i := 0; i < 15; i++ {
+		assert.Equal(t, send("/v1/books/suggest?q=hob"), http.StatusOK)
+	}
+
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, send("/v1/books"), http.StatusOK)
+	}
+
+	assert.Equal(t, send("/v1/books"), http.StatusTooManyRequests)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/svenrisse/bookshelf/internal/models"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+// createProgressHandler godoc
+//
+//	@Summary		Log reading progress on a shelved Book
+//	@Description	give either page or percentage; the entry moves to reading, or to read on the last page
+//	@Tags			progress
+//	@Accept			json
+//	@Produce		json
+//	@Param			bookid	path		int	true	"Book ID"
+//	@Success		201		{object}	models.Progress
+//	@Failure		400
+//	@Failure		401
+//	@Failure		404
+//	@Failure		409
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/user/books/{bookid}/progress [post]
+func (app *application) createProgressHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, err := app.readBookIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		Page       *int32   `json:"page"`
+		Percentage *float32 `json:"percentage"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+
+	v.Check(input.Page != nil || input.Percentage != nil, "page", "either page or percentage must be provided")
+	v.Check(input.Page == nil || input.Percentage == nil, "page", "must not be provided together with percentage")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	userBook, err := app.models.UserBook.GetForUser(int64(user.ID), bookID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	book, err := app.models.Books.GetIncludingDeleted(bookID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	progress := models.NewProgress(userBook.ID, input.Page, input.Percentage, book.Pages)
+
+	if models.ValidateProgress(v, progress, book.Pages); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	progress.Apply(userBook, book.Pages)
+
+	err = app.models.Progress.Insert(progress, userBook)
+	if err != nil {
+		if errors.Is(err, models.ErrEditConflict) {
+			app.editConflictResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"progress": progress, "userBook": userBook}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// listProgressHandler godoc
+//
+//	@Summary	List the reading progress logged on a shelved Book
+//	@Tags		progress
+//	@Produce	json
+//	@Param		bookid	path	int	true	"Book ID"
+//	@Success	200		{array}	models.Progress
+//	@Failure	401
+//	@Failure	404
+//	@Failure	500
+//	@Router		/v1/user/books/{bookid}/progress [get]
+func (app *application) listProgressHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, err := app.readBookIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	userBook, err := app.models.UserBook.GetForUser(int64(user.ID), bookID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	progress, err := app.models.Progress.ListForUserBook(userBook.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"progress": progress}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
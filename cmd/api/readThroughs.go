@@ -0,0 +1,234 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/svenrisse/bookshelf/internal/models"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+// createReadThroughHandler godoc
+//
+//	@Summary	Record a read-through of a shelved Book
+//	@Tags		read-throughs
+//	@Accept		json
+//	@Produce	json
+//	@Param		bookid	path		int	true	"Book ID"
+//	@Success	201		{object}	models.ReadThrough
+//	@Failure	400
+//	@Failure	401
+//	@Failure	404
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/user/books/{bookid}/reads [post]
+func (app *application) createReadThroughHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, err := app.readBookIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		StartedAt  *time.Time `json:"startedAt"`
+		FinishedAt *time.Time `json:"finishedAt"`
+		Rating     float32    `json:"rating"`
+		Notes      string     `json:"notes"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	userBook, err := app.models.UserBook.GetForUser(int64(user.ID), bookID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	readThrough := &models.ReadThrough{
+		UserBookID: userBook.ID,
+		StartedAt:  input.StartedAt,
+		FinishedAt: input.FinishedAt,
+		Rating:     input.Rating,
+		Notes:      input.Notes,
+	}
+
+	v := validator.New()
+	if models.ValidateReadThrough(v, readThrough); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.ReadThroughs.Insert(readThrough)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"readThrough": readThrough}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// updateReadThroughHandler godoc
+//
+//	@Summary	Update a read-through of a shelved Book
+//	@Tags		read-throughs
+//	@Accept		json
+//	@Produce	json
+//	@Param		bookid	path		int	true	"Book ID"
+//	@Param		id		path		int	true	"Read-through ID"
+//	@Success	200		{object}	models.ReadThrough
+//	@Failure	400
+//	@Failure	401
+//	@Failure	404
+//	@Failure	409
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/user/books/{bookid}/reads/{id} [patch]
+func (app *application) updateReadThroughHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, err := app.readBookIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	userBook, err := app.models.UserBook.GetForUser(int64(user.ID), bookID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	readThrough, err := app.models.ReadThroughs.GetForUserBook(userBook.ID, id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	var input struct {
+		StartedAt  *time.Time `json:"startedAt"`
+		FinishedAt *time.Time `json:"finishedAt"`
+		Rating     *float32   `json:"rating"`
+		Notes      *string    `json:"notes"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.StartedAt != nil {
+		readThrough.StartedAt = input.StartedAt
+	}
+	if input.FinishedAt != nil {
+		readThrough.FinishedAt = input.FinishedAt
+	}
+	if input.Rating != nil {
+		readThrough.Rating = *input.Rating
+	}
+	if input.Notes != nil {
+		readThrough.Notes = *input.Notes
+	}
+
+	v := validator.New()
+	if models.ValidateReadThrough(v, readThrough); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.ReadThroughs.Update(readThrough)
+	if err != nil {
+		if errors.Is(err, models.ErrEditConflict) {
+			app.editConflictResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"readThrough": readThrough}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// deleteReadThroughHandler godoc
+//
+//	@Summary	Delete a read-through of a shelved Book
+//	@Tags		read-throughs
+//	@Produce	json
+//	@Param		bookid	path	int	true	"Book ID"
+//	@Param		id		path	int	true	"Read-through ID"
+//	@Success	200
+//	@Failure	401
+//	@Failure	404
+//	@Failure	500
+//	@Router		/v1/user/books/{bookid}/reads/{id} [delete]
+func (app *application) deleteReadThroughHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, err := app.readBookIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	userBook, err := app.models.UserBook.GetForUser(int64(user.ID), bookID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.models.ReadThroughs.DeleteForUserBook(userBook.ID, id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "read-through successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/svenrisse/bookshelf/internal/models"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+// listBookReviewsHandler godoc
+//
+//	@Summary	List the reviews of all editions of a Book
+//	@Tags		reviews
+//	@Produce	json
+//	@Param		id			path		int		true	"Book ID"
+//	@Param		page		query		int		false	"Page"
+//	@Param		page_size	query		int		false	"Page size"
+//	@Param		sort		query		string	false	"-reviewed_at (newest), -rating (highest rating) or -helpful_count (most helpful)"
+//	@Success	200			{array}		models.Review
+//	@Failure	404
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/books/{id}/reviews [get]
+func (app *application) listBookReviewsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		models.Filters
+	}
+
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	input.Filters.Page = app.readInt(qs, "page", 1, v)
+	input.PageSize = app.readInt(qs, "page_size", 20, v)
+	input.Sort = app.readString(qs, "sort", "-reviewed_at")
+	input.SortSafeList = []string{
+		"reviewed_at",
+		"rating",
+		"helpful_count",
+		"-reviewed_at",
+		"-rating",
+		"-helpful_count",
+	}
+
+	if models.ValidateFilters(v, input.Filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	book, err := app.models.Books.Get(id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	reviews, metadata, err := app.models.Reviews.ListForWork(book.WorkID, input.Filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"reviews": reviews, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// getReviewHandler godoc
+//
+//	@Summary	Get a Review
+//	@Tags		reviews
+//	@Produce	json
+//	@Param		id	path		int	true	"Review ID"
+//	@Success	200	{object}	models.Review
+//	@Failure	404
+//	@Failure	500
+//	@Router		/v1/reviews/{id} [get]
+func (app *application) getReviewHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	review, err := app.models.Reviews.Get(id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"review": review}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// createReviewHelpfulVoteHandler godoc
+//
+//	@Summary	Mark a Review as helpful
+//	@Tags		reviews
+//	@Produce	json
+//	@Param		id	path	int	true	"Review ID"
+//	@Success	201
+//	@Failure	401
+//	@Failure	404
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/reviews/{id}/helpful [post]
+func (app *application) createReviewHelpfulVoteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	_, err = app.models.Reviews.Get(id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	err = app.models.Reviews.AddHelpfulVote(id, int64(user.ID))
+	if err != nil {
+		v := validator.New()
+
+		switch {
+		case errors.Is(err, models.ErrOwnReview):
+			v.AddError("review", "you can't vote for your own review")
+			app.failedValidationResponse(w, r, v.Errors)
+		case errors.Is(err, models.ErrDuplicateHelpfulVote):
+			v.AddError("review", "you already voted for this review")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"message": "review marked as helpful"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// deleteReviewHelpfulVoteHandler godoc
+//
+//	@Summary	Remove the helpful vote of the current User from a Review
+//	@Tags		reviews
+//	@Produce	json
+//	@Param		id	path	int	true	"Review ID"
+//	@Success	200
+//	@Failure	401
+//	@Failure	404
+//	@Failure	500
+//	@Router		/v1/reviews/{id}/helpful [delete]
+func (app *application) deleteReviewHelpfulVoteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	err = app.models.Reviews.DeleteHelpfulVote(id, int64(user.ID))
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "helpful vote successfully removed"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
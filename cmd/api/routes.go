@@ -6,6 +6,7 @@ import (
 
 	"github.com/julienschmidt/httprouter"
 	_ "github.com/svenrisse/bookshelf/docs"
+	"github.com/svenrisse/bookshelf/internal/models"
 )
 
 func (app *application) routes() http.Handler {
@@ -23,16 +24,93 @@ func (app *application) routes() http.Handler {
 	router.HandlerFunc(http.MethodGet, "/v1/auth/:provider/logout", app.AuthLogout)
 	router.HandlerFunc(http.MethodGet, "/v1/auth/:provider", app.Auth)
 
+	router.HandlerFunc(http.MethodPost, "/v1/tokens", app.requireAuthenticatedUser(app.createAuthenticationTokenHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/tokens", app.requireAuthenticatedUser(app.listAuthenticationTokensHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/tokens/:id", app.requireAuthenticatedUser(app.deleteAuthenticationTokenHandler))
+
 	router.HandlerFunc(http.MethodGet, "/v1/books", app.listBooksHandler)
-	router.HandlerFunc(http.MethodPost, "/v1/books", app.createBookHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/books", app.requirePermission(models.PermissionBooksWrite, app.createBookHandler))
 	router.HandlerFunc(http.MethodGet, "/v1/books/:id", app.getBookHandler)
-	router.HandlerFunc(http.MethodPatch, "/v1/books/:id", app.updateBookHandler)
-	router.HandlerFunc(http.MethodDelete, "/v1/books/:id", app.deleteBookHandler)
+	router.HandlerFunc(http.MethodPatch, "/v1/books/:id", app.requirePermission(models.PermissionBooksWrite, app.updateBookHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/books/:id", app.requirePermission(models.PermissionBooksWrite, app.deleteBookHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/books/:id/reviews", app.listBookReviewsHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/books/:id/merge", app.requirePermission(models.PermissionBooksAdmin, app.mergeBookHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/books/:id/restore", app.requirePermission(models.PermissionBooksAdmin, app.restoreBookHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/authors", app.listAuthorsHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/authors", app.requirePermission(models.PermissionBooksWrite, app.createAuthorHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/authors/:id", app.getAuthorHandler)
+	router.HandlerFunc(http.MethodPatch, "/v1/authors/:id", app.requirePermission(models.PermissionBooksWrite, app.updateAuthorHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/authors/:id", app.requirePermission(models.PermissionBooksWrite, app.deleteAuthorHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/authors/:id/books", app.listAuthorBooksHandler)
+
+	router.HandlerFunc(http.MethodGet, "/v1/series", app.listSeriesHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/series", app.requirePermission(models.PermissionBooksWrite, app.createSeriesHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/series/:id", app.getSeriesHandler)
+	router.HandlerFunc(http.MethodPatch, "/v1/series/:id", app.requirePermission(models.PermissionBooksWrite, app.updateSeriesHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/series/:id", app.requirePermission(models.PermissionBooksWrite, app.deleteSeriesHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/series/:id/books/:bookid", app.requirePermission(models.PermissionBooksWrite, app.addSeriesBookHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/series/:id/books/:bookid", app.requirePermission(models.PermissionBooksWrite, app.removeSeriesBookHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/works/:id", app.getWorkHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/works/merge", app.requirePermission(models.PermissionBooksAdmin, app.mergeWorksHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/genres", app.listGenresHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/genres", app.requirePermission(models.PermissionBooksAdmin, app.createGenreHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/genres/:id", app.getGenreHandler)
+	router.HandlerFunc(http.MethodPatch, "/v1/genres/:id", app.requirePermission(models.PermissionBooksAdmin, app.updateGenreHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/genres/:id/merge", app.requirePermission(models.PermissionBooksAdmin, app.mergeGenreHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/reviews/:id", app.getReviewHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/reviews/:id/helpful", app.requireAuthenticatedUser(app.createReviewHelpfulVoteHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/reviews/:id/helpful", app.requireAuthenticatedUser(app.deleteReviewHelpfulVoteHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/user/books", app.requireAuthenticatedUser(app.listUsersBooksHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/user/books", app.requireAuthenticatedUser(app.createUsersBooksHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/user/books/:bookid", app.requireAuthenticatedUser(app.getUsersBooksHandler))
+	router.HandlerFunc(http.MethodPatch, "/v1/user/books/:bookid", app.requireAuthenticatedUser(app.updateUsersBooksHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/user/books/:bookid", app.requireAuthenticatedUser(app.deleteUsersBooksHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/user/books/:bookid/progress", app.requireAuthenticatedUser(app.listProgressHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/user/books/:bookid/progress", app.requireAuthenticatedUser(app.createProgressHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/user/books/:bookid/reads", app.requireAuthenticatedUser(app.createReadThroughHandler))
+	router.HandlerFunc(http.MethodPatch, "/v1/user/books/:bookid/reads/:id", app.requireAuthenticatedUser(app.updateReadThroughHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/user/books/:bookid/reads/:id", app.requireAuthenticatedUser(app.deleteReadThroughHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/user/shelves", app.requireAuthenticatedUser(app.listShelvesHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/user/shelves", app.requireAuthenticatedUser(app.createShelfHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/user/shelves/:id", app.requireAuthenticatedUser(app.getShelfHandler))
+	router.HandlerFunc(http.MethodPatch, "/v1/user/shelves/:id", app.requireAuthenticatedUser(app.updateShelfHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/user/shelves/:id", app.requireAuthenticatedUser(app.deleteShelfHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/user/shelves/:id/books/:bookid", app.requireAuthenticatedUser(app.addShelfBookHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/user/shelves/:id/books/:bookid", app.requireAuthenticatedUser(app.removeShelfBookHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/user/tags", app.requireAuthenticatedUser(app.listTagsHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/user/tags", app.requireAuthenticatedUser(app.createTagHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/user/tags/:id", app.requireAuthenticatedUser(app.getTagHandler))
+	router.HandlerFunc(http.MethodPatch, "/v1/user/tags/:id", app.requireAuthenticatedUser(app.updateTagHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/user/tags/:id", app.requireAuthenticatedUser(app.deleteTagHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/user/goals", app.requireAuthenticatedUser(app.listGoalsHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/user/goals", app.requireAuthenticatedUser(app.createGoalHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/user/goals/:year", app.requireAuthenticatedUser(app.getGoalHandler))
+	router.HandlerFunc(http.MethodPatch, "/v1/user/goals/:year", app.requireAuthenticatedUser(app.updateGoalHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/user/goals/:year", app.requireAuthenticatedUser(app.deleteGoalHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/user/series/next", app.requireAuthenticatedUser(app.listNextInSeriesHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/user/stats", app.requireAuthenticatedUser(app.getStatsHandler))
+
+	router.HandlerFunc(http.MethodPost, "/v1/user/imports", app.requireAuthenticatedUser(app.createImportHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/user/imports/:id", app.requireAuthenticatedUser(app.getImportHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/user/export", app.requireAuthenticatedUser(app.getExportHandler))
+
+	// httprouter doesn't allow static segments next to a parameter, so the
+	// routes that share a prefix with /v1/books/:id are matched first here.
+	mux := http.NewServeMux()
 
-	router.HandlerFunc(http.MethodGet, "/v1/user/books", app.listUsersBooksHandler)
-	router.HandlerFunc(http.MethodPost, "/v1/user/books", app.createUsersBooksHandler)
-	router.HandlerFunc(http.MethodPatch, "/v1/user/books/:bookid", app.updateUsersBooksHandler)
-	router.HandlerFunc(http.MethodDelete, "/v1/user/books/:bookid", app.deleteUsersBooksHandler)
+	mux.HandleFunc("GET /v1/books/isbn/{isbn}", app.getBookByISBNHandler)
+	mux.HandleFunc("GET /v1/books/suggest", app.suggestBooksHandler)
+	mux.Handle("/", router)
 
-	return app.metrics(app.recoverPanic(app.enableCORS(app.rateLimit((router)))))
+	return app.metrics(app.recoverPanic(app.enableCORS(app.rateLimit(app.authenticate(mux)))))
 }
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/svenrisse/bookshelf/internal/models"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+// createSeriesHandler godoc
+//
+//	@Summary	Create a Series
+//	@Tags		series
+//	@Accept		json
+//	@Produce	json
+//	@Success	201	{object}	models.Series
+//	@Failure	400
+//	@Failure	403
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/series [post]
+func (app *application) createSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	series := &models.Series{
+		Name:        input.Name,
+		Description: input.Description,
+	}
+
+	v := validator.New()
+	if models.ValidateSeries(v, series); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Series.Insert(series)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/series/%d", series.ID))
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"series": series}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// listSeriesHandler godoc
+//
+//	@Summary	List Series
+//	@Tags		series
+//	@Produce	json
+//	@Param		name		query	string	false	"Part of the name"
+//	@Param		page		query	int		false	"Page"
+//	@Param		page_size	query	int		false	"Page size"
+//	@Param		sort		query	string	false	"name, id, -name or -id"
+//	@Success	200			{array}	models.Series
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/series [get]
+func (app *application) listSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name string
+		models.Filters
+	}
+
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	input.Name = app.readString(qs, "name", "")
+	input.Filters.Page = app.readInt(qs, "page", 1, v)
+	input.PageSize = app.readInt(qs, "page_size", 20, v)
+	input.Sort = app.readString(qs, "sort", "name")
+	input.SortSafeList = []string{"id", "name", "-id", "-name"}
+
+	if models.ValidateFilters(v, input.Filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	series, metadata, err := app.models.Series.List(input.Name, input.Filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"series": series, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// getSeriesHandler godoc
+//
+//	@Summary		Get a Series with its volumes in reading order
+//	@Description	Every volume carries the reading status it has on the caller's shelf.
+//	@Tags			series
+//	@Produce		json
+//	@Param			id	path		int	true	"Series ID"
+//	@Success		200	{object}	models.Series
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/series/{id} [get]
+func (app *application) getSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	series, err := app.models.Series.Get(id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	series.Volumes, err = app.models.Series.Volumes(series.ID, int64(user.ID))
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"series": series}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// updateSeriesHandler godoc
+//
+//	@Summary	Update a Series
+//	@Tags		series
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		int	true	"Series ID"
+//	@Success	200	{object}	models.Series
+//	@Failure	400
+//	@Failure	403
+//	@Failure	404
+//	@Failure	409
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/series/{id} [patch]
+func (app *application) updateSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	series, err := app.models.Series.Get(id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	var input struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.Name != nil {
+		series.Name = *input.Name
+	}
+	if input.Description != nil {
+		series.Description = *input.Description
+	}
+
+	v := validator.New()
+	if models.ValidateSeries(v, series); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Series.Update(series)
+	if err != nil {
+		if errors.Is(err, models.ErrEditConflict) {
+			app.editConflictResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"series": series}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// deleteSeriesHandler godoc
+//
+//	@Summary		Delete a Series
+//	@Description	the books of the series are kept
+//	@Tags			series
+//	@Produce		json
+//	@Param			id	path	int	true	"Series ID"
+//	@Success		200
+//	@Failure		403
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/series/{id} [delete]
+func (app *application) deleteSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Series.Delete(id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "series successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// addSeriesBookHandler godoc
+//
+//	@Summary		Add a Book to a Series at a position
+//	@Description	adding a book again moves it to the new position
+//	@Tags			series
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int	true	"Series ID"
+//	@Param			bookid	path		int	true	"Book ID"
+//	@Success		200		{object}	models.Series
+//	@Failure		400
+//	@Failure		403
+//	@Failure		404
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/series/{id}/books/{bookid} [put]
+func (app *application) addSeriesBookHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	bookID, err := app.readBookIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		Position *float64 `json:"position"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+
+	v.Check(input.Position != nil, "position", "must be provided")
+	if input.Position != nil {
+		models.ValidateVolumePosition(v, *input.Position)
+	}
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	series, err := app.models.Series.Get(id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	_, err = app.models.Books.Get(bookID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.models.Series.AddBook(series.ID, bookID, *input.Position)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	series.Volumes, err = app.models.Series.Volumes(series.ID, int64(user.ID))
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"series": series}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// removeSeriesBookHandler godoc
+//
+//	@Summary	Remove a Book from a Series
+//	@Tags		series
+//	@Produce	json
+//	@Param		id		path	int	true	"Series ID"
+//	@Param		bookid	path	int	true	"Book ID"
+//	@Success	200
+//	@Failure	403
+//	@Failure	404
+//	@Failure	500
+//	@Router		/v1/series/{id}/books/{bookid} [delete]
+func (app *application) removeSeriesBookHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	bookID, err := app.readBookIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Series.RemoveBook(id, bookID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "book successfully removed from series"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// listNextInSeriesHandler godoc
+//
+//	@Summary		List the next unread volume of every series the User has started
+//	@Description	A series counts as started once one of its volumes is read. Series without unread volumes are left out.
+//	@Tags			series
+//	@Produce		json
+//	@Success		200	{array}	models.NextVolume
+//	@Failure		401
+//	@Failure		500
+//	@Router			/v1/user/series/next [get]
+func (app *application) listNextInSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	user := app.contextGetUser(r)
+
+	next, err := app.models.Series.NextUnreadForUser(int64(user.ID))
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"next": next}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
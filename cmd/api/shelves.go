@@ -0,0 +1,392 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/svenrisse/bookshelf/internal/models"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+// createShelfHandler godoc
+//
+//	@Summary	Create a Shelf for the current User
+//	@Tags		shelves
+//	@Accept		json
+//	@Produce	json
+//	@Success	201	{object}	models.Shelf
+//	@Failure	400
+//	@Failure	401
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/user/shelves [post]
+func (app *application) createShelfHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	shelf := &models.Shelf{
+		UserID:      int64(user.ID),
+		Name:        input.Name,
+		Description: input.Description,
+	}
+
+	v := validator.New()
+	if models.ValidateShelf(v, shelf); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Shelves.Insert(shelf)
+	if err != nil {
+		if errors.Is(err, models.ErrDuplicateShelfName) {
+			v.AddError("name", "a shelf with this name already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"shelf": shelf}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// listShelvesHandler godoc
+//
+//	@Summary	List the Shelves of the current User
+//	@Tags		shelves
+//	@Produce	json
+//	@Success	200	{array}	models.Shelf
+//	@Failure	401
+//	@Failure	500
+//	@Router		/v1/user/shelves [get]
+func (app *application) listShelvesHandler(w http.ResponseWriter, r *http.Request) {
+	user := app.contextGetUser(r)
+
+	shelves, err := app.models.Shelves.ListForUser(int64(user.ID))
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"shelves": shelves}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// getShelfHandler godoc
+//
+//	@Summary	Get a Shelf with its books in order
+//	@Tags		shelves
+//	@Produce	json
+//	@Param		id	path		int	true	"Shelf ID"
+//	@Success	200	{object}	models.Shelf
+//	@Failure	401
+//	@Failure	404
+//	@Failure	500
+//	@Router		/v1/user/shelves/{id} [get]
+func (app *application) getShelfHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	shelf, err := app.models.Shelves.GetForUser(int64(user.ID), id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"shelf": shelf}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// updateShelfHandler godoc
+//
+//	@Summary	Update a Shelf
+//	@Tags		shelves
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		int	true	"Shelf ID"
+//	@Success	200	{object}	models.Shelf
+//	@Failure	400
+//	@Failure	401
+//	@Failure	404
+//	@Failure	409
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/user/shelves/{id} [patch]
+func (app *application) updateShelfHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	shelf, err := app.models.Shelves.GetForUser(int64(user.ID), id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	var input struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.Name != nil {
+		shelf.Name = *input.Name
+	}
+	if input.Description != nil {
+		shelf.Description = *input.Description
+	}
+
+	v := validator.New()
+	if models.ValidateShelf(v, shelf); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Shelves.Update(shelf)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		case errors.Is(err, models.ErrDuplicateShelfName):
+			v.AddError("name", "a shelf with this name already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"shelf": shelf}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// deleteShelfHandler godoc
+//
+//	@Summary		Delete a Shelf
+//	@Description	the books stay in the library of the user
+//	@Tags			shelves
+//	@Produce		json
+//	@Param			id	path	int	true	"Shelf ID"
+//	@Success		200
+//	@Failure		401
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/user/shelves/{id} [delete]
+func (app *application) deleteShelfHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	err = app.models.Shelves.DeleteForUser(int64(user.ID), id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "shelf successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// addShelfBookHandler godoc
+//
+//	@Summary		Put a shelved Book on a Shelf
+//	@Description	the book is appended unless a position is given in the optional body; putting it on the shelf again moves it
+//	@Tags			shelves
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path	int	true	"Shelf ID"
+//	@Param			bookid	path	int	true	"Book ID"
+//	@Success		200
+//	@Failure		400
+//	@Failure		401
+//	@Failure		404
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/user/shelves/{id}/books/{bookid} [put]
+func (app *application) addShelfBookHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	bookID, err := app.readBookIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		Position *int32 `json:"position"`
+	}
+
+	// the body is optional, books are appended to the end of the shelf
+	// unless a position is given
+	if r.ContentLength != 0 {
+		err = app.readJSON(w, r, &input)
+		if err != nil {
+			app.badRequestResponse(w, r, err)
+			return
+		}
+	}
+
+	v := validator.New()
+
+	if input.Position != nil {
+		v.Check(*input.Position >= 0, "position", "must not be negative")
+	}
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	shelf, err := app.models.Shelves.GetForUser(int64(user.ID), id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	userBook, err := app.models.UserBook.GetForUser(int64(user.ID), bookID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	position, err := app.models.Shelves.AddBook(shelf.ID, userBook.ID, input.Position)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	env := envelope{"shelf_id": shelf.ID, "book_id": userBook.BookID, "position": position}
+
+	err = app.writeJSON(w, http.StatusOK, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// removeShelfBookHandler godoc
+//
+//	@Summary	Take a Book off a Shelf
+//	@Tags		shelves
+//	@Produce	json
+//	@Param		id		path	int	true	"Shelf ID"
+//	@Param		bookid	path	int	true	"Book ID"
+//	@Success	200
+//	@Failure	401
+//	@Failure	404
+//	@Failure	500
+//	@Router		/v1/user/shelves/{id}/books/{bookid} [delete]
+func (app *application) removeShelfBookHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	bookID, err := app.readBookIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	shelf, err := app.models.Shelves.GetForUser(int64(user.ID), id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	userBook, err := app.models.UserBook.GetForUser(int64(user.ID), bookID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.models.Shelves.RemoveBook(shelf.ID, userBook.ID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "book successfully removed from shelf"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/svenrisse/bookshelf/internal/models"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+// getStatsHandler godoc
+//
+//	@Summary	Get the reading statistics of the current User
+//	@Tags		stats
+//	@Produce	json
+//	@Param		year	query		int	false	"Calendar year, all time if omitted"
+//	@Success	200		{object}	models.Stats
+//	@Failure	401
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/user/stats [get]
+func (app *application) getStatsHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	year := app.readInt(r.URL.Query(), "year", 0, v)
+
+	if models.ValidateStatsYear(v, year); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	stats, err := app.models.Stats.ForUser(int64(user.ID), year)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"stats": stats}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/svenrisse/bookshelf/internal/models"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+// createTagHandler godoc
+//
+//	@Summary	Create a Tag for the current User
+//	@Tags		tags
+//	@Accept		json
+//	@Produce	json
+//	@Success	201	{object}	models.Tag
+//	@Failure	400
+//	@Failure	401
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/user/tags [post]
+func (app *application) createTagHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name string `json:"name"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	tag := &models.Tag{
+		UserID: int64(user.ID),
+		Name:   input.Name,
+	}
+
+	v := validator.New()
+	if models.ValidateTag(v, tag); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Tags.Insert(tag)
+	if err != nil {
+		if errors.Is(err, models.ErrDuplicateTagName) {
+			v.AddError("name", "a tag with this name already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"tag": tag}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// listTagsHandler godoc
+//
+//	@Summary	List the Tags of the current User, the most used first
+//	@Tags		tags
+//	@Produce	json
+//	@Success	200	{array}	models.Tag
+//	@Failure	401
+//	@Failure	500
+//	@Router		/v1/user/tags [get]
+func (app *application) listTagsHandler(w http.ResponseWriter, r *http.Request) {
+	user := app.contextGetUser(r)
+
+	tags, err := app.models.Tags.ListForUser(int64(user.ID))
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"tags": tags}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// getTagHandler godoc
+//
+//	@Summary	Get a Tag
+//	@Tags		tags
+//	@Produce	json
+//	@Param		id	path		int	true	"Tag ID"
+//	@Success	200	{object}	models.Tag
+//	@Failure	401
+//	@Failure	404
+//	@Failure	500
+//	@Router		/v1/user/tags/{id} [get]
+func (app *application) getTagHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	tag, err := app.models.Tags.GetForUser(int64(user.ID), id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"tag": tag}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// updateTagHandler godoc
+//
+//	@Summary	Rename a Tag
+//	@Tags		tags
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		int	true	"Tag ID"
+//	@Success	200	{object}	models.Tag
+//	@Failure	400
+//	@Failure	401
+//	@Failure	404
+//	@Failure	409
+//	@Failure	422
+//	@Failure	500
+//	@Router		/v1/user/tags/{id} [patch]
+func (app *application) updateTagHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	tag, err := app.models.Tags.GetForUser(int64(user.ID), id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	var input struct {
+		Name *string `json:"name"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.Name != nil {
+		tag.Name = *input.Name
+	}
+
+	v := validator.New()
+	if models.ValidateTag(v, tag); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Tags.Update(tag)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		case errors.Is(err, models.ErrDuplicateTagName):
+			v.AddError("name", "a tag with this name already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"tag": tag}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// deleteTagHandler godoc
+//
+//	@Summary		Delete a Tag
+//	@Description	the tag is removed from all books of the user
+//	@Tags			tags
+//	@Produce		json
+//	@Param			id	path	int	true	"Tag ID"
+//	@Success		200
+//	@Failure		401
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/user/tags/{id} [delete]
+func (app *application) deleteTagHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	err = app.models.Tags.DeleteForUser(int64(user.ID), id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "tag successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/svenrisse/bookshelf/internal/models"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+// createAuthenticationTokenHandler godoc
+//
+//	@Summary		Create an API token for the current User
+//	@Description	tokens can only be created from a browser session; ttl_days defaults to 30
+//	@Tags			tokens
+//	@Accept			json
+//	@Produce		json
+//	@Success		201	{object}	models.Token
+//	@Failure		400
+//	@Failure		401
+//	@Failure		403
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/tokens [post]
+func (app *application) createAuthenticationTokenHandler(w http.ResponseWriter, r *http.Request) {
+	// tokens are minted from a browser session only, so that a leaked token
+	// can't be used to create further tokens
+	if r.Header.Get("Authorization") != "" {
+		app.sessionRequiredResponse(w, r)
+		return
+	}
+
+	var input struct {
+		TTLDays int `json:"ttl_days"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.TTLDays == 0 {
+		input.TTLDays = 30
+	}
+
+	v := validator.New()
+
+	v.Check(input.TTLDays > 0, "ttl_days", "must be greater than zero")
+	v.Check(input.TTLDays <= 365, "ttl_days", "must not be more than 365")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	token, err := app.models.Tokens.New(
+		int64(user.ID),
+		time.Duration(input.TTLDays)*24*time.Hour,
+		models.ScopeAuthentication,
+	)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"authentication_token": token}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// listAuthenticationTokensHandler godoc
+//
+//	@Summary	List the API tokens of the current User
+//	@Tags		tokens
+//	@Produce	json
+//	@Success	200	{array}	models.Token
+//	@Failure	401
+//	@Failure	500
+//	@Router		/v1/tokens [get]
+func (app *application) listAuthenticationTokensHandler(w http.ResponseWriter, r *http.Request) {
+	user := app.contextGetUser(r)
+
+	tokens, err := app.models.Tokens.GetAllForUser(int64(user.ID))
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"authentication_tokens": tokens}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// deleteAuthenticationTokenHandler godoc
+//
+//	@Summary	Revoke an API token of the current User
+//	@Tags		tokens
+//	@Produce	json
+//	@Param		id	path	int	true	"Token ID"
+//	@Success	200
+//	@Failure	401
+//	@Failure	404
+//	@Failure	500
+//	@Router		/v1/tokens/{id} [delete]
+func (app *application) deleteAuthenticationTokenHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	err = app.models.Tokens.DeleteForUser(int64(user.ID), id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "token successfully revoked"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/svenrisse/bookshelf/internal/models"
@@ -11,12 +13,13 @@ import (
 func (app *application) createUsersBooksHandler(w http.ResponseWriter, r *http.Request) {
 	var input struct {
 		BookID     int64     `json:"bookID"`
-		UserID     int64     `json:"userID"`
 		Read       bool      `json:"read"`
+		Status     string    `json:"status,omitempty"`
 		Rating     float32   `json:"rating,omitempty"`
 		ReviewBody string    `json:"reviewBody,omitempty"`
 		ReadAt     time.Time `json:"readAt,omitempty"`
 		ReviewedAt time.Time `json:"reviewedAt,omitempty"`
+		Tags       []string  `json:"tags,omitempty"`
 	}
 
 	err := app.readJSON(w, r, &input)
@@ -25,14 +28,30 @@ func (app *application) createUsersBooksHandler(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	user := app.contextGetUser(r)
+
 	userBook := &models.UserBook{
 		BookID:     input.BookID,
-		UserID:     input.UserID,
+		UserID:     int64(user.ID),
 		Read:       input.Read,
 		Rating:     input.Rating,
 		ReviewBody: input.ReviewBody,
 		ReadAt:     input.ReadAt,
 		ReviewedAt: input.ReviewedAt,
+		Tags:       input.Tags,
+	}
+
+	switch {
+	case input.Status != "":
+		userBook.SetStatus(input.Status)
+	case input.Read:
+		userBook.SetStatus(models.StatusRead)
+	default:
+		userBook.SetStatus(models.StatusWantToRead)
+	}
+
+	if userBook.ReviewBody != "" && userBook.ReviewedAt.IsZero() {
+		userBook.ReviewedAt = time.Now()
 	}
 
 	v := validator.New()
@@ -41,8 +60,24 @@ func (app *application) createUsersBooksHandler(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	_, err = app.models.Books.Get(userBook.BookID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			v.AddError("bookID", "book does not exist")
+			app.failedValidationResponse(w, r, v.Errors)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
 	err = app.models.UserBook.Insert(userBook)
 	if err != nil {
+		if errors.Is(err, models.ErrDuplicateUserBook) {
+			v.AddError("bookID", "book is already on your shelf")
+			app.failedValidationResponse(w, r, v.Errors)
+			return
+		}
 		app.serverErrorResponse(w, r, err)
 		return
 	}
@@ -53,6 +88,276 @@ func (app *application) createUsersBooksHandler(w http.ResponseWriter, r *http.R
 	}
 }
 
-func (app *application) deleteUsersBooksHandler(w http.ResponseWriter, r *http.Request) {}
-func (app *application) listUsersBooksHandler(w http.ResponseWriter, r *http.Request)   {}
-func (app *application) updateUsersBooksHandler(w http.ResponseWriter, r *http.Request) {}
+func (app *application) updateUsersBooksHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, err := app.readBookIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	userBook, err := app.models.UserBook.GetForUser(int64(user.ID), bookID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if !app.checkIfMatch(w, r, userBook.Version) {
+		return
+	}
+
+	var input struct {
+		Read       *bool      `json:"read"`
+		Status     *string    `json:"status"`
+		Rating     *float32   `json:"rating"`
+		ReviewBody *string    `json:"reviewBody"`
+		ReadAt     *time.Time `json:"readAt"`
+		ReviewedAt *time.Time `json:"reviewedAt"`
+		Tags       []string   `json:"tags"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	switch {
+	case input.Status != nil:
+		userBook.SetStatus(*input.Status)
+	case input.Read != nil && *input.Read:
+		userBook.SetStatus(models.StatusRead)
+	case input.Read != nil && userBook.Status == models.StatusRead:
+		userBook.SetStatus(models.StatusWantToRead)
+		userBook.ReadAt = time.Time{}
+	}
+	if input.Rating != nil {
+		userBook.Rating = *input.Rating
+	}
+	if input.ReviewBody != nil {
+		userBook.ReviewBody = *input.ReviewBody
+
+		// A cleared review has no date; a changed one is dated now unless the
+		// client says otherwise.
+		switch {
+		case userBook.ReviewBody == "":
+			userBook.ReviewedAt = time.Time{}
+		case input.ReviewedAt == nil:
+			userBook.ReviewedAt = time.Now()
+		}
+	}
+	if input.ReadAt != nil {
+		userBook.ReadAt = *input.ReadAt
+	}
+	if input.ReviewedAt != nil && userBook.ReviewBody != "" {
+		userBook.ReviewedAt = *input.ReviewedAt
+	}
+	if input.Tags != nil {
+		userBook.Tags = input.Tags
+	}
+
+	v := validator.New()
+	if models.ValidateUserBook(v, userBook); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.UserBook.Update(userBook)
+	if err != nil {
+		if errors.Is(err, models.ErrEditConflict) {
+			app.editConflictResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	env, etag, err := app.userBookRepresentation(userBook)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("ETag", etag)
+
+	err = app.writeJSON(w, http.StatusOK, env, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *application) deleteUsersBooksHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, err := app.readBookIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	userBook, err := app.models.UserBook.GetForUser(int64(user.ID), bookID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if !app.checkIfMatch(w, r, userBook.Version) {
+		return
+	}
+
+	err = app.models.UserBook.DeleteForUser(int64(user.ID), bookID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "book successfully removed from shelf"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *application) listUsersBooksHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		models.UserBookFilters
+		models.Filters
+	}
+
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	input.Read = app.readBool(qs, "read", v)
+	input.Status = app.readString(qs, "status", "")
+	input.RatingMin = app.readFloat(qs, "rating_min", 0, v)
+	input.RatingMax = app.readFloat(qs, "rating_max", 0, v)
+	input.ReadAfter = app.readDate(qs, "read_after", v)
+	input.ReadBefore = app.readDate(qs, "read_before", v)
+	input.Shelf = int64(app.readInt(qs, "shelf", 0, v))
+	input.Tags = app.readCSV(qs, "tags", []string{})
+
+	input.Filters.Page = app.readInt(qs, "page", 1, v)
+	input.PageSize = app.readInt(qs, "page_size", 20, v)
+	input.Cursor = app.readString(qs, "cursor", "")
+	input.Sort = app.readString(qs, "sort", "-added_at")
+	input.SortSafeList = []string{
+		"id",
+		"added_at",
+		"read_at",
+		"rating",
+		"position",
+		"-id",
+		"-added_at",
+		"-read_at",
+		"-rating",
+		"-position",
+	}
+
+	if input.Shelf == 0 {
+		v.Check(!strings.HasSuffix(input.Sort, "position"), "sort", "sorting by position requires a shelf")
+	}
+
+	models.ValidateUserBookFilters(v, input.UserBookFilters)
+	if models.ValidateFilters(v, input.Filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	if input.Shelf != 0 {
+		_, err := app.models.Shelves.GetForUser(int64(user.ID), input.Shelf)
+		if err != nil {
+			if errors.Is(err, models.ErrRecordNotFound) {
+				v.AddError("shelf", "shelf does not exist")
+				app.failedValidationResponse(w, r, v.Errors)
+				return
+			}
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	userBooks, metadata, err := app.models.UserBook.ListForUser(
+		int64(user.ID),
+		input.UserBookFilters,
+		input.Filters,
+	)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"userBooks": userBooks, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *application) getUsersBooksHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, err := app.readBookIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	userBook, err := app.models.UserBook.GetForUser(int64(user.ID), bookID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	env, etag, err := app.userBookRepresentation(userBook)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if app.notModified(w, r, etag) {
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// userBookRepresentation returns the body sent for a shelved book, with its
+// read-throughs, and its ETag. Reading and updating the entry both build it
+// here, so that they hand out the same tag for the same entry.
+func (app *application) userBookRepresentation(userBook *models.UserBook) (envelope, string, error) {
+	readThroughs, err := app.models.ReadThroughs.ListForUserBook(userBook.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	env := envelope{"userBook": userBook, "readThroughs": readThroughs}
+
+	etag, err := contentETag(userBook.Version, env)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return env, etag, nil
+}
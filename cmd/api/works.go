@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/svenrisse/bookshelf/internal/models"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+// getWorkHandler godoc
+//
+//	@Summary	Get a Work with its editions and their combined ratings
+//	@Tags		works
+//	@Produce	json
+//	@Param		id	path		int	true	"Work ID"
+//	@Success	200	{object}	models.Work
+//	@Failure	404
+//	@Failure	500
+//	@Router		/v1/works/{id} [get]
+func (app *application) getWorkHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	work, err := app.models.Works.Get(id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			app.notFoundResponse(w, r)
+			return
+		}
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	ratings, err := app.models.Reviews.RatingStatsForWork(work.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"work": work, "ratings": ratings}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// mergeWorksHandler godoc
+//
+//	@Summary		Merge two Books into one Work
+//	@Description	moves book_id and the other editions of its work into the work of into_book_id
+//	@Tags			works
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.Work
+//	@Failure		400
+//	@Failure		403
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/works/merge [post]
+func (app *application) mergeWorksHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		BookID     int64 `json:"book_id"`
+		IntoBookID int64 `json:"into_book_id"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+
+	v.Check(input.BookID > 0, "book_id", "must be provided")
+	v.Check(input.IntoBookID > 0, "into_book_id", "must be provided")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	workID, err := app.models.Works.Merge(input.BookID, input.IntoBookID)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrRecordNotFound):
+			v.AddError("book_id", "both books must exist")
+			app.failedValidationResponse(w, r, v.Errors)
+		case errors.Is(err, models.ErrSameWork):
+			v.AddError("book_id", "is already an edition of the same work")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	work, err := app.models.Works.Get(workID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"work": work}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
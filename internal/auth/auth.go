@@ -2,6 +2,7 @@ package auth
 
 import (
 	"log"
+	"net/http"
 	"os"
 
 	"github.com/gorilla/sessions"
@@ -40,3 +41,48 @@ func NewAuth() {
 	goth.UseProviders(discord.New(discordClientId, discordClientSecret, "http://localhost:4000/v1/auth/discord/callback"))
 	goth.UseProviders(github.New(githubClientId, githubClientSecret, "http://localhost:4000/v1/auth/github/callback"))
 }
+
+const (
+	sessionName   = "bookshelf_session"
+	sessionUserID = "user_id"
+)
+
+// SetSessionUser stores the ID of the logged-in user in the application
+// session. The gothic session is cleared once CompleteUserAuth returns, so it
+// cannot be used to identify the user on later requests.
+func SetSessionUser(w http.ResponseWriter, r *http.Request, userID int) error {
+	session, err := gothic.Store.Get(r, sessionName)
+	if err != nil {
+		return err
+	}
+
+	session.Values[sessionUserID] = userID
+
+	return session.Save(r, w)
+}
+
+// GetSessionUser returns the ID of the user stored in the application session.
+// ok is false when the request carries no valid session.
+func GetSessionUser(r *http.Request) (userID int, ok bool) {
+	session, err := gothic.Store.Get(r, sessionName)
+	if err != nil {
+		return 0, false
+	}
+
+	userID, ok = session.Values[sessionUserID].(int)
+	return userID, ok
+}
+
+// ClearSession logs the user out by emptying the application session and
+// telling the browser to drop its cookie.
+func ClearSession(w http.ResponseWriter, r *http.Request) error {
+	session, err := gothic.Store.Get(r, sessionName)
+	if err != nil {
+		return err
+	}
+
+	session.Options.MaxAge = -1
+	session.Values = make(map[any]any)
+
+	return session.Save(r, w)
+}
@@ -0,0 +1,224 @@
+// Package goodreads reads and writes the library export that Goodreads
+// offers under "My Books > Import and export".
+package goodreads
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The exclusive shelves every Goodreads library has. Every book is on exactly
+// one of them.
+const (
+	ShelfToRead           = "to-read"
+	ShelfCurrentlyReading = "currently-reading"
+	ShelfRead             = "read"
+)
+
+// dateLayout is the format of the Date Read and Date Added columns.
+const dateLayout = "2006/01/02"
+
+var requiredColumns = []string{"Title", "Author"}
+
+// Record is a single row of the export. Row is the line number of the record
+// in the file, counting the header as row 1.
+type Record struct {
+	Row            int
+	Title          string
+	Author         string
+	ISBN           string
+	Rating         int
+	Review         string
+	Pages          int32
+	Year           int32
+	DateRead       time.Time
+	DateAdded      time.Time
+	ExclusiveShelf string
+	Shelves        []string
+	ReadCount      int
+}
+
+// Read parses a Goodreads export. Columns are looked up by name, so exports
+// with reordered or additional columns are accepted, but Title and Author are
+// required. Values that cannot be parsed are left at their zero value.
+func Read(r io.Reader) ([]Record, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("file is empty")
+		}
+		return nil, err
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+
+	for _, name := range requiredColumns {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("missing %q column", name)
+		}
+	}
+
+	records := []Record{}
+
+	for row := 2; ; row++ {
+		fields, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		get := func(name string) string {
+			i, ok := index[name]
+			if !ok || i >= len(fields) {
+				return ""
+			}
+			return strings.TrimSpace(fields[i])
+		}
+
+		record := Record{
+			Row:            row,
+			Title:          get("Title"),
+			Author:         get("Author"),
+			ISBN:           unquoteISBN(get("ISBN13")),
+			Review:         get("My Review"),
+			ExclusiveShelf: get("Exclusive Shelf"),
+		}
+
+		if record.ISBN == "" {
+			record.ISBN = unquoteISBN(get("ISBN"))
+		}
+
+		record.Rating, _ = strconv.Atoi(get("My Rating"))
+		record.ReadCount, _ = strconv.Atoi(get("Read Count"))
+
+		if pages, err := strconv.ParseInt(get("Number of Pages"), 10, 32); err == nil {
+			record.Pages = int32(pages)
+		}
+
+		for _, column := range []string{"Original Publication Year", "Year Published"} {
+			if year, err := strconv.ParseInt(get(column), 10, 32); err == nil && year > 0 {
+				record.Year = int32(year)
+				break
+			}
+		}
+
+		record.DateRead, _ = time.Parse(dateLayout, get("Date Read"))
+		record.DateAdded, _ = time.Parse(dateLayout, get("Date Added"))
+
+		for _, shelf := range strings.Split(get("Bookshelves"), ",") {
+			shelf = strings.TrimSpace(shelf)
+
+			switch shelf {
+			case "", ShelfToRead, ShelfCurrentlyReading, ShelfRead:
+				continue
+			}
+
+			record.Shelves = append(record.Shelves, shelf)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// columns is the header of a Goodreads export, in the order Goodreads writes
+// it.
+var columns = []string{
+	"Book Id", "Title", "Author", "Author l-f", "Additional Authors", "ISBN", "ISBN13",
+	"My Rating", "Average Rating", "Publisher", "Binding", "Number of Pages", "Year Published",
+	"Original Publication Year", "Date Read", "Date Added", "Bookshelves", "Bookshelves with positions",
+	"Exclusive Shelf", "My Review", "Spoiler", "Private Notes", "Read Count", "Owned Copies",
+}
+
+// Writer writes records in the format of a Goodreads export, so that the
+// file can be imported by Goodreads and by the services that accept its
+// exports. Columns this application has no data for are left empty.
+type Writer struct {
+	w *csv.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: csv.NewWriter(w)}
+}
+
+func (w *Writer) WriteHeader() error {
+	return w.w.Write(columns)
+}
+
+func (w *Writer) Write(record Record) error {
+	shelves := record.Shelves
+	if record.ExclusiveShelf != "" {
+		shelves = append([]string{record.ExclusiveShelf}, shelves...)
+	}
+
+	fields := make([]string, len(columns))
+
+	for i, column := range columns {
+		switch column {
+		case "Title":
+			fields[i] = record.Title
+		case "Author":
+			fields[i] = record.Author
+		case "ISBN13":
+			if record.ISBN != "" {
+				fields[i] = fmt.Sprintf("=%q", record.ISBN)
+			}
+		case "My Rating":
+			fields[i] = strconv.Itoa(record.Rating)
+		case "Number of Pages":
+			if record.Pages > 0 {
+				fields[i] = strconv.Itoa(int(record.Pages))
+			}
+		case "Original Publication Year":
+			if record.Year > 0 {
+				fields[i] = strconv.Itoa(int(record.Year))
+			}
+		case "Date Read":
+			if !record.DateRead.IsZero() {
+				fields[i] = record.DateRead.Format(dateLayout)
+			}
+		case "Date Added":
+			if !record.DateAdded.IsZero() {
+				fields[i] = record.DateAdded.Format(dateLayout)
+			}
+		case "Bookshelves":
+			fields[i] = strings.Join(shelves, ", ")
+		case "Exclusive Shelf":
+			fields[i] = record.ExclusiveShelf
+		case "My Review":
+			fields[i] = record.Review
+		case "Read Count":
+			fields[i] = strconv.Itoa(record.ReadCount)
+		}
+	}
+
+	return w.w.Write(fields)
+}
+
+// Flush writes any buffered data to the underlying io.Writer and reports any
+// error that occurred during a previous Write or Flush.
+func (w *Writer) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// unquoteISBN strips the spreadsheet formula Goodreads wraps ISBNs in, so
+// that ="0261103571" becomes 0261103571.
+func unquoteISBN(s string) string {
+	s = strings.TrimPrefix(s, "=")
+	return strings.Trim(s, `"`)
+}
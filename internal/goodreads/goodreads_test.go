@@ -0,0 +1,99 @@
+package goodreads
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+)
+
+const export = `Book Id,Title,Author,Author l-f,Additional Authors,ISBN,ISBN13,My Rating,Average Rating,Publisher,Binding,Number of Pages,Year Published,Original Publication Year,Date Read,Date Added,Bookshelves,Bookshelves with positions,Exclusive Shelf,My Review,Spoiler,Private Notes,Read Count,Owned Copies
+5907,The Hobbit,J.R.R. Tolkien,"Tolkien, J.R.R.",,"=""0261103571""","=""9780261103573""",5,4.29,HarperCollins,Paperback,310,1999,1937,2024/04/10,2023/12/24,"favorites, read","favorites (#1), read (#12)",read,"Loved it, again.",,,2,1
+13496,A Game of Thrones,George R.R. Martin,"Martin, George R.R.",,"=""""","=""""",0,4.44,Bantam,Mass Market Paperback,,2005,,,2024/01/05,to-read,to-read (#3),to-read,,,,0,0
+`
+
+func TestRead(t *testing.T) {
+	records, err := Read(strings.NewReader(export))
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(records), 2)
+
+	hobbit := records[0]
+	assert.Equal(t, hobbit.Row, 2)
+	assert.Equal(t, hobbit.Title, "The Hobbit")
+	assert.Equal(t, hobbit.Author, "J.R.R. Tolkien")
+	assert.Equal(t, hobbit.ISBN, "9780261103573")
+	assert.Equal(t, hobbit.Rating, 5)
+	assert.Equal(t, hobbit.Review, "Loved it, again.")
+	assert.Equal(t, hobbit.Pages, int32(310))
+	assert.Equal(t, hobbit.Year, int32(1937))
+	assert.Equal(t, hobbit.DateRead, time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, hobbit.DateAdded, time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, hobbit.ExclusiveShelf, ShelfRead)
+	assert.Equal(t, len(hobbit.Shelves), 1)
+	assert.Equal(t, hobbit.Shelves[0], "favorites")
+
+	got := records[1]
+	assert.Equal(t, got.ISBN, "")
+	assert.Equal(t, got.Pages, int32(0))
+	assert.Equal(t, got.Year, int32(2005))
+	assert.Equal(t, got.DateRead.IsZero(), true)
+	assert.Equal(t, got.ExclusiveShelf, ShelfToRead)
+	assert.Equal(t, len(got.Shelves), 0)
+}
+
+func TestReadMissingColumn(t *testing.T) {
+	_, err := Read(strings.NewReader("Book Id,Title\n1,The Hobbit\n"))
+
+	assert.StringContains(t, err.Error(), `missing "Author" column`)
+}
+
+func TestReadEmpty(t *testing.T) {
+	_, err := Read(strings.NewReader(""))
+
+	assert.StringContains(t, err.Error(), "file is empty")
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	want := Record{
+		Row:            2,
+		Title:          "The Hobbit",
+		Author:         "J.R.R. Tolkien",
+		ISBN:           "9780261103573",
+		Rating:         5,
+		Review:         "Loved it, again.",
+		Pages:          310,
+		Year:           1937,
+		DateRead:       time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC),
+		DateAdded:      time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC),
+		ExclusiveShelf: ShelfRead,
+		Shelves:        []string{"favorites"},
+		ReadCount:      2,
+	}
+
+	var buf strings.Builder
+
+	w := NewWriter(&buf)
+	assert.NilError(t, w.WriteHeader())
+	assert.NilError(t, w.Write(want))
+	assert.NilError(t, w.Flush())
+
+	records, err := Read(strings.NewReader(buf.String()))
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(records), 1)
+
+	got := records[0]
+	assert.Equal(t, got.Title, want.Title)
+	assert.Equal(t, got.ISBN, want.ISBN)
+	assert.Equal(t, got.Rating, want.Rating)
+	assert.Equal(t, got.Review, want.Review)
+	assert.Equal(t, got.Pages, want.Pages)
+	assert.Equal(t, got.Year, want.Year)
+	assert.Equal(t, got.DateRead, want.DateRead)
+	assert.Equal(t, got.DateAdded, want.DateAdded)
+	assert.Equal(t, got.ExclusiveShelf, want.ExclusiveShelf)
+	assert.Equal(t, got.Shelves[0], "favorites")
+	assert.Equal(t, got.ReadCount, want.ReadCount)
+}
@@ -0,0 +1,444 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+var (
+	ErrDuplicateAuthor = errors.New("duplicate author")
+	ErrAuthorNotFound  = errors.New("author not found")
+)
+
+const (
+	RoleAuthor      = "author"
+	RoleTranslator  = "translator"
+	RoleEditor      = "editor"
+	RoleIllustrator = "illustrator"
+)
+
+var Roles = []string{RoleAuthor, RoleTranslator, RoleEditor, RoleIllustrator}
+
+// bylineSeparatorRX splits a byline such as "Neil Gaiman & Terry Pratchett"
+// into its names. Migration 000015 splits the existing bylines the same way.
+var bylineSeparatorRX = regexp.MustCompile(`\s*(;|&|\sand\s)\s*`)
+
+type Author struct {
+	ID        int64     `json:"id"   example:"12"`
+	Name      string    `json:"name" example:"J.R.R. Tolkien"`
+	Bio       string    `json:"bio"  example:"English writer and philologist."`
+	CreatedAt time.Time `json:"-"`
+	Version   int32     `json:"-"`
+}
+
+// BookAuthor links a book to one of its authors, translators, editors or
+// illustrators. Position orders the contributors within a book.
+type BookAuthor struct {
+	AuthorID int64  `json:"id"   example:"12"`
+	Name     string `json:"name" example:"J.R.R. Tolkien"`
+	Role     string `json:"role" example:"author"`
+}
+
+// AuthoredBook is a book as listed for one of its contributors.
+type AuthoredBook struct {
+	*Book
+	BookID int64  `json:"id"`
+	Role   string `json:"role"`
+}
+
+func ValidateAuthor(v *validator.Validator, author *Author) {
+	v.Check(strings.TrimSpace(author.Name) != "", "name", "must be provided")
+	v.Check(len(author.Name) <= 300, "name", "must not be more than 300 bytes long")
+
+	v.Check(len(author.Bio) <= 10_000, "bio", "must not be more than 10000 bytes long")
+}
+
+func ValidateBookAuthors(v *validator.Validator, authors []BookAuthor) {
+	v.Check(len(authors) <= 20, "authors", "must not contain more than 20 entries")
+
+	for i, author := range authors {
+		v.Check(author.AuthorID > 0, fmt.Sprintf("authors[%d].id", i), "must be a positive integer")
+		v.Check(validator.PermittedValue(author.Role, Roles...), fmt.Sprintf("authors[%d].role", i), "invalid role value")
+	}
+}
+
+// SplitByline returns the names in a byline, in order and without blanks.
+func SplitByline(byline string) []string {
+	names := []string{}
+
+	for _, name := range bylineSeparatorRX.Split(byline, -1) {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+type AuthorModel struct {
+	DB *sql.DB
+}
+
+func (m AuthorModel) Insert(author *Author) error {
+	query := `
+    INSERT INTO authors (name, bio)
+    VALUES ($1, $2)
+    RETURNING id, created_at, version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, author.Name, author.Bio).Scan(&author.ID, &author.CreatedAt, &author.Version)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "authors_name_key_key"):
+			return ErrDuplicateAuthor
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m AuthorModel) Get(id int64) (*Author, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+    SELECT id, name, bio, created_at, version
+    FROM authors
+    WHERE id = $1`
+
+	var author Author
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, id).
+		Scan(&author.ID, &author.Name, &author.Bio, &author.CreatedAt, &author.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return &author, nil
+}
+
+func (m AuthorModel) List(name string, filters Filters) ([]*Author, Metadata, error) {
+	query := fmt.Sprintf(`
+    SELECT count(*) OVER(), id, name, bio, created_at, version
+    FROM authors
+    WHERE (name ILIKE $4 OR $1 = '')
+    ORDER BY %s %s, id ASC
+    LIMIT $2 OFFSET $3`, filters.sortColumn(), filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, name, filters.limit(), filters.offset(), containsPattern(name))
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	authors := []*Author{}
+
+	for rows.Next() {
+		var author Author
+
+		err := rows.Scan(&totalRecords, &author.ID, &author.Name, &author.Bio, &author.CreatedAt, &author.Version)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		authors = append(authors, &author)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+
+	return authors, metadata, nil
+}
+
+func (m AuthorModel) Update(author *Author) error {
+	query := `
+    UPDATE authors
+    SET name = $1, bio = $2, version = version + 1
+    WHERE id = $3 AND version = $4
+    RETURNING version`
+
+	args := []any{author.Name, author.Bio, author.ID, author.Version}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&author.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEditConflict
+		case strings.Contains(err.Error(), "authors_name_key_key"):
+			return ErrDuplicateAuthor
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete removes the author and their links to books. The bylines of those
+// books are left as they are.
+func (m AuthorModel) Delete(id int64) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	query := `
+    DELETE FROM authors
+    WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (m AuthorModel) ListForBook(bookID int64) ([]BookAuthor, error) {
+	query := `
+    SELECT a.id, a.name, ba.role
+    FROM books_authors ba
+    INNER JOIN authors a ON a.id = ba.author_id
+    WHERE ba.book_id = $1
+    ORDER BY ba.position ASC, a.name ASC`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, bookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	authors := []BookAuthor{}
+
+	for rows.Next() {
+		var author BookAuthor
+
+		err := rows.Scan(&author.AuthorID, &author.Name, &author.Role)
+		if err != nil {
+			return nil, err
+		}
+
+		authors = append(authors, author)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return authors, nil
+}
+
+func (m AuthorModel) ListBooks(authorID int64, filters Filters) ([]*AuthoredBook, Metadata, error) {
+	query := fmt.Sprintf(`
+    SELECT count(*) OVER(), ba.role, %s
+    FROM books_authors ba
+    INNER JOIN books ON books.id = ba.book_id
+    WHERE ba.author_id = $1 AND books.deleted_at IS NULL
+    ORDER BY books.%s %s, books.id ASC
+    LIMIT $2 OFFSET $3`, qualifiedBookColumns, filters.sortColumn(), filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, authorID, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	books := []*AuthoredBook{}
+
+	for rows.Next() {
+		book := AuthoredBook{Book: &Book{}}
+
+		err := rows.Scan(append([]any{&totalRecords, &book.Role}, scanBookDest(book.Book)...)...)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		book.BookID = book.Book.ID
+
+		books = append(books, &book)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+
+	return books, metadata, nil
+}
+
+// Exist reports whether every one of the contributors refers to an existing
+// author.
+func (m AuthorModel) Exist(authors []BookAuthor) (bool, error) {
+	ids := make([]int64, 0, len(authors))
+	unique := make(map[int64]bool, len(authors))
+
+	for _, author := range authors {
+		ids = append(ids, author.AuthorID)
+		unique[author.AuthorID] = true
+	}
+
+	query := `
+    SELECT count(*)
+    FROM authors
+    WHERE id = ANY($1)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var count int
+
+	err := m.DB.QueryRowContext(ctx, query, pq.Array(ids)).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count == len(unique), nil
+}
+
+// SetForBook replaces all contributors of the book. It returns
+// ErrRecordNotFound when one of the authors doesn't exist.
+func (m AuthorModel) SetForBook(bookID int64, authors []BookAuthor) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = setBookAuthors(ctx, tx, bookID, authors)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func setBookAuthors(ctx context.Context, tx *sql.Tx, bookID int64, authors []BookAuthor) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM books_authors WHERE book_id = $1`, bookID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+    INSERT INTO books_authors (book_id, author_id, role, position)
+    VALUES ($1, $2, $3, $4)
+    ON CONFLICT (book_id, author_id, role) DO NOTHING`
+
+	for i, author := range authors {
+		_, err = tx.ExecContext(ctx, query, bookID, author.AuthorID, author.Role, i)
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "books_authors_author_id_fkey"):
+				return ErrRecordNotFound
+			default:
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// LinkByline replaces the author-role contributors of the book with the names
+// in its byline, creating authors that don't exist yet. Translators, editors
+// and illustrators are kept.
+func (m AuthorModel) LinkByline(bookID int64, byline string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = linkBookByline(ctx, tx, bookID, byline)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func linkBookByline(ctx context.Context, tx *sql.Tx, bookID int64, byline string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM books_authors WHERE book_id = $1 AND role = 'author'`, bookID)
+	if err != nil {
+		return err
+	}
+
+	// The no-op update makes RETURNING yield the id of an existing author.
+	upsert := `
+    INSERT INTO authors (name)
+    VALUES ($1)
+    ON CONFLICT (name_key) DO UPDATE SET name = authors.name
+    RETURNING id`
+
+	link := `
+    INSERT INTO books_authors (book_id, author_id, role, position)
+    VALUES ($1, $2, 'author', $3)
+    ON CONFLICT (book_id, author_id, role) DO NOTHING`
+
+	for i, name := range SplitByline(byline) {
+		var authorID int64
+
+		err = tx.QueryRowContext(ctx, upsert, name).Scan(&authorID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, link, bookID, authorID, i)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+func TestAuthorModel_Insert(t *testing.T) {
+	tests := []struct {
+		name    string
+		author  Author
+		wantErr error
+	}{
+		{name: "Valid author", author: Author{Name: "Ursula K. Le Guin"}, wantErr: nil},
+		{name: "Same name, other spelling", author: Author{Name: "J. R. R. Tolkien"}, wantErr: ErrDuplicateAuthor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := NewTestDB(t)
+
+			m := AuthorModel{db}
+
+			err := m.Insert(&tt.author)
+
+			assert.Equal(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestAuthorModel_LinkByline(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := AuthorModel{db}
+
+	err := m.LinkByline(1, "J.R.R. Tolkien & Christopher Tolkien")
+	assert.NilError(t, err)
+
+	authors, err := m.ListForBook(1)
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(authors), 2)
+	assert.Equal(t, authors[0].AuthorID, int64(1))
+	assert.Equal(t, authors[1].Name, "Christopher Tolkien")
+}
+
+func TestSplitByline(t *testing.T) {
+	tests := []struct {
+		name   string
+		byline string
+		want   []string
+	}{
+		{name: "Single author", byline: "J.R.R. Tolkien", want: []string{"J.R.R. Tolkien"}},
+		{name: "Ampersand", byline: "Neil Gaiman & Terry Pratchett", want: []string{"Neil Gaiman", "Terry Pratchett"}},
+		{name: "And", byline: "Neil Gaiman and Terry Pratchett", want: []string{"Neil Gaiman", "Terry Pratchett"}},
+		{name: "Semicolon and blanks", byline: " A; ;B ", want: []string{"A", "B"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitByline(tt.byline)
+
+			assert.Equal(t, len(got), len(tt.want))
+			for i := range tt.want {
+				assert.Equal(t, got[i], tt.want[i])
+			}
+		})
+	}
+}
+
+func TestValidateBookAuthors(t *testing.T) {
+	tests := []struct {
+		name      string
+		authors   []BookAuthor
+		wantError map[string]string
+	}{
+		{name: "Valid", authors: []BookAuthor{{AuthorID: 1, Role: RoleAuthor}, {AuthorID: 2, Role: RoleTranslator}}, wantError: nil},
+		{name: "Invalid role", authors: []BookAuthor{{AuthorID: 1, Role: "narrator"}}, wantError: map[string]string{"authors[0].role": "invalid role value"}},
+		{name: "Missing id", authors: []BookAuthor{{Role: RoleAuthor}}, wantError: map[string]string{"authors[0].id": "must be a positive integer"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validator.New()
+
+			ValidateBookAuthors(v, tt.authors)
+			assert.DeepEqual(t, tt.wantError, v.Errors)
+		})
+	}
+}
@@ -5,26 +5,92 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/lib/pq"
 	"github.com/svenrisse/bookshelf/internal/validator"
 )
 
+var ErrDuplicateISBN = errors.New("duplicate isbn")
+
+// LanguageRX matches ISO 639-1 and ISO 639-2 language codes.
+var LanguageRX = regexp.MustCompile("^[a-z]{2,3}$")
+
+// Highlight options for ts_headline. Titles and authors are short and
+// highlighted in full, descriptions are cut down to the fragment that matched.
+const (
+	highlightAll      = "StartSel=<b>, StopSel=</b>, HighlightAll=true"
+	highlightFragment = "StartSel=<b>, StopSel=</b>, MaxFragments=1, MaxWords=30, MinWords=10"
+)
+
+// Highlight holds the fields of a book that matched a search, with the
+// matched words wrapped in <b> tags. The text itself is not HTML-escaped.
+type Highlight struct {
+	Title       string `json:"title"       example:"The <b>Hobbit</b>"`
+	Author      string `json:"author"      example:"J.R.R. Tolkien"`
+	Description string `json:"description" example:"Bilbo Baggins is a <b>hobbit</b> who enjoys a comfortable life."`
+}
+
 // Book model info
 // @Description Book information
 type Book struct {
-	ID        int64     `json:"-"      example:"5"`
-	CreatedAt time.Time `json:"-"`
-	Title     string    `json:"title"  example:"The Hobbit"`
-	Author    string    `json:"author" example:"J.R.R. Tolkien"`
-	Year      int32     `json:"year"   example:"1937"`
-	Pages     int32     `json:"pages"  example:"320"`
-	Genres    []string  `json:"genres" example:"Fantasy,Epic,Children's literature"`
-	Version   int32     `json:"-"`
+	ID            int64        `json:"-"              example:"5"`
+	WorkID        int64        `json:"work_id"        example:"5"`
+	CreatedAt     time.Time    `json:"-"`
+	Title         string       `json:"title"          example:"The Hobbit"`
+	OriginalTitle string       `json:"original_title" example:"The Hobbit, or There and Back Again"`
+	Author        string       `json:"author"         example:"J.R.R. Tolkien"`
+	Year          int32        `json:"year"           example:"1937"`
+	Pages         int32        `json:"pages"          example:"320"`
+	Genres        []string     `json:"genres"         example:"Fantasy,Epic,Children's literature"`
+	ISBN          string       `json:"isbn"           example:"9780261103573"`
+	Publisher     string       `json:"publisher"      example:"HarperCollins"`
+	Language      string       `json:"language"       example:"en"`
+	Description   string       `json:"description"    example:"Bilbo Baggins is a hobbit who enjoys a comfortable life."`
+	CoverURL      string       `json:"cover_url"      example:"https://covers.openlibrary.org/b/isbn/9780261103573-L.jpg"`
+	Authors       []BookAuthor `json:"authors,omitempty"`
+	Highlight     *Highlight   `json:"highlight,omitempty"`
+	Version       int32        `json:"version"        example:"3"`
 }
 
-func ValidateBook(v *validator.Validator, book *Book) {
+// bookColumns are the columns scanned by scanBookDest, in the same order.
+const bookColumns = `id, work_id, created_at, title, original_title, author, year, pages, genres,
+    COALESCE(isbn, ''), publisher, language, description, cover_url, version`
+
+// qualifiedBookColumns are bookColumns for queries that join books with other
+// tables.
+const qualifiedBookColumns = `books.id, books.work_id, books.created_at, books.title, books.original_title, books.author,
+    books.year, books.pages, books.genres, COALESCE(books.isbn, ''), books.publisher, books.language,
+    books.description, books.cover_url, books.version`
+
+func scanBookDest(book *Book) []any {
+	return []any{
+		&book.ID,
+		&book.WorkID,
+		&book.CreatedAt,
+		&book.Title,
+		&book.OriginalTitle,
+		&book.Author,
+		&book.Year,
+		&book.Pages,
+		pq.Array(&book.Genres),
+		&book.ISBN,
+		&book.Publisher,
+		&book.Language,
+		&book.Description,
+		&book.CoverURL,
+		&book.Version,
+	}
+}
+
+// ValidateBook checks the book and replaces aliases in its genres with their
+// names. Without a vocabulary, genres are taken as they are.
+func ValidateBook(v *validator.Validator, book *Book, genres GenreVocabulary) {
 	v.Check(book.Title != "", "title", "must be provided")
 	v.Check(len(book.Title) <= 500, "title", "must not be more than 500 bytes long")
 
@@ -40,25 +106,113 @@ func ValidateBook(v *validator.Validator, book *Book) {
 	v.Check(book.Genres != nil, "genres", "must be provided")
 	v.Check(len(book.Genres) >= 0, "genres", "must contain atleast 1 genre")
 	v.Check(len(book.Genres) <= 10, "genres", "must not contain more than 10 genres")
+
+	if genres != nil {
+		for i, genre := range book.Genres {
+			name, ok := genres.Canonical(genre)
+			v.Check(ok, "genres", "must only contain known genres")
+			if ok {
+				book.Genres[i] = name
+			}
+		}
+	}
+
 	v.Check(validator.Unique(book.Genres), "genres", "must not contain duplicate values")
+
+	ValidateBookAuthors(v, book.Authors)
+
+	v.Check(book.WorkID >= 0, "work_id", "must be a positive integer")
+
+	if book.ISBN != "" {
+		v.Check(ValidISBN13(book.ISBN), "isbn", "must be a valid ISBN-10 or ISBN-13")
+	}
+
+	v.Check(len(book.OriginalTitle) <= 500, "original_title", "must not be more than 500 bytes long")
+	v.Check(len(book.Publisher) <= 300, "publisher", "must not be more than 300 bytes long")
+	v.Check(len(book.Description) <= 10_000, "description", "must not be more than 10000 bytes long")
+
+	if book.Language != "" {
+		v.Check(validator.Matches(book.Language, LanguageRX), "language", "must be a lowercase ISO 639 language code")
+	}
+
+	if book.CoverURL != "" {
+		v.Check(len(book.CoverURL) <= 2000, "cover_url", "must not be more than 2000 bytes long")
+		v.Check(validHTTPURL(book.CoverURL), "cover_url", "must be an http or https URL")
+	}
+}
+
+func validHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
 }
 
 type BookModel struct {
 	DB *sql.DB
 }
 
+// Insert adds the book along with its contributors: the Authors of the book,
+// or the names in its byline when it has none. Either both are stored or
+// neither is. ErrAuthorNotFound is returned when one of the Authors doesn't
+// exist.
 func (b BookModel) Insert(book *Book) error {
 	query := `
-    INSERT INTO books (title, author, year, pages, genres)
-    VALUES ($1, $2, $3, $4, $5,)
-    RETURNING id, created_at, version`
+    INSERT INTO books (title, original_title, author, year, pages, genres, isbn, publisher, language, description, cover_url, work_id)
+    VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10, $11, NULLIF($12, 0))
+    RETURNING id, work_id, created_at, version`
 
-	args := []any{book.Title, book.Author, book.Year, book.Pages, pq.Array(book.Genres)}
+	args := []any{
+		book.Title,
+		book.OriginalTitle,
+		book.Author,
+		book.Year,
+		book.Pages,
+		pq.Array(book.Genres),
+		book.ISBN,
+		book.Publisher,
+		book.Language,
+		book.Description,
+		book.CoverURL,
+		book.WorkID,
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	return b.DB.QueryRowContext(ctx, query, args...).Scan(&book.ID, &book.CreatedAt, &book.Version)
+	tx, err := b.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&book.ID, &book.WorkID, &book.CreatedAt, &book.Version)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "books_isbn_key"):
+			return ErrDuplicateISBN
+		case strings.Contains(err.Error(), "books_work_id_fkey"):
+			return ErrWorkNotFound
+		default:
+			return err
+		}
+	}
+
+	if len(book.Authors) > 0 {
+		err = setBookAuthors(ctx, tx, book.ID, book.Authors)
+		if errors.Is(err, ErrRecordNotFound) {
+			return ErrAuthorNotFound
+		}
+	} else {
+		err = linkBookByline(ctx, tx, book.ID, book.Author)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (b BookModel) Get(id int64) (*Book, error) {
@@ -67,17 +221,49 @@ func (b BookModel) Get(id int64) (*Book, error) {
 	}
 
 	query := `
-    SELECT id, created_at, title, author, year, pages, genres, version 
+    SELECT ` + bookColumns + `
+    FROM books
+    WHERE id = $1 AND deleted_at IS NULL`
+
+	return b.get(query, id)
+}
+
+// GetIncludingDeleted looks a book up like Get, but also finds soft-deleted
+// books, which stay on the shelves of the users that have them.
+func (b BookModel) GetIncludingDeleted(id int64) (*Book, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+    SELECT ` + bookColumns + `
     FROM books
     WHERE id = $1`
 
+	return b.get(query, id)
+}
+
+// GetByISBN looks a book up by its ISBN-13.
+func (b BookModel) GetByISBN(isbn string) (*Book, error) {
+	if isbn == "" {
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+    SELECT ` + bookColumns + `
+    FROM books
+    WHERE isbn = $1 AND deleted_at IS NULL`
+
+	return b.get(query, isbn)
+}
+
+func (b BookModel) get(query string, args ...any) (*Book, error) {
 	var book Book
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	err := b.DB.QueryRowContext(ctx, query, id).
-		Scan(&book.ID, &book.CreatedAt, &book.Title, &book.Author, &book.Year, &book.Pages, &book.Genres, &book.Version)
+	err := b.DB.QueryRowContext(ctx, query, args...).Scan(scanBookDest(&book)...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrRecordNotFound
@@ -88,19 +274,39 @@ func (b BookModel) Get(id int64) (*Book, error) {
 	return &book, nil
 }
 
+// GetByTitleAuthor looks a book up by its title and author, ignoring case.
+// When several books match, the oldest one is returned.
+func (b BookModel) GetByTitleAuthor(title, author string) (*Book, error) {
+	query := `
+    SELECT ` + bookColumns + `
+    FROM books
+    WHERE lower(title) = lower($1) AND lower(author) = lower($2) AND deleted_at IS NULL
+    ORDER BY id ASC
+    LIMIT 1`
+
+	return b.get(query, title, author)
+}
+
 func (b BookModel) Update(book *Book) error {
 	query := `
     UPDATE books
-    SET title = $1, author = $2, year = $3, pages = $4, genres = $5, version = version + 1
-    WHERE id = $6 AND version = $7
+    SET title = $1, original_title = $2, author = $3, year = $4, pages = $5, genres = $6, isbn = NULLIF($7, ''),
+        publisher = $8, language = $9, description = $10, cover_url = $11, version = version + 1
+    WHERE id = $12 AND version = $13 AND deleted_at IS NULL
     RETURNING version`
 
 	args := []any{
 		book.Title,
+		book.OriginalTitle,
 		book.Author,
 		book.Year,
 		book.Pages,
 		pq.Array(book.Genres),
+		book.ISBN,
+		book.Publisher,
+		book.Language,
+		book.Description,
+		book.CoverURL,
 		book.ID,
 		book.Version,
 	}
@@ -110,22 +316,30 @@ func (b BookModel) Update(book *Book) error {
 
 	err := b.DB.QueryRowContext(ctx, query, args...).Scan(&book.Version)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
 			return ErrEditConflict
+		case strings.Contains(err.Error(), "books_isbn_key"):
+			return ErrDuplicateISBN
+		default:
+			return err
 		}
-		return err
 	}
 
 	return nil
 }
 
+// Delete soft-deletes the book. It is hidden from the catalog, but stays on the
+// shelves of the users that have it and can be restored.
 func (b BookModel) Delete(id int64) error {
 	if id < 1 {
 		return ErrRecordNotFound
 	}
 
-	query := `DELETE FROM books
-    WHERE id = $1`
+	query := `
+    UPDATE books
+    SET deleted_at = NOW(), version = version + 1
+    WHERE id = $1 AND deleted_at IS NULL`
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -147,23 +361,192 @@ func (b BookModel) Delete(id int64) error {
 	return nil
 }
 
-func (b BookModel) ListBooks(
-	title string,
-	genres []string,
-	filters Filters,
-) ([]*Book, Metadata, error) {
+// Restore brings back a soft-deleted book. ErrDuplicateISBN is returned when
+// another book has taken its ISBN since.
+func (b BookModel) Restore(id int64) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	query := `
+    UPDATE books
+    SET deleted_at = NULL, version = version + 1
+    WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := b.DB.ExecContext(ctx, query, id)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "books_isbn_key"):
+			return ErrDuplicateISBN
+		default:
+			return err
+		}
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// CountUsers returns the number of users that have the book on their shelf.
+func (b BookModel) CountUsers(id int64) (int, error) {
+	query := `
+    SELECT count(*)
+    FROM usersBooksRelation
+    WHERE bookId = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var count int
+
+	err := b.DB.QueryRowContext(ctx, query, id).Scan(&count)
+	return count, err
+}
+
+// BookFilters narrow down the catalog. Zero values don't filter. Genres must
+// all be present, GenresAny needs one of them and GenresNot none of them. With
+// Subgenres, a genre is also present when one of its subgenres is. RatingMin
+// compares against the average rating of the work.
+type BookFilters struct {
+	Title     string
+	Search    string
+	Author    string
+	Language  string
+	Genres    []string
+	GenresAny []string
+	GenresNot []string
+	YearMin   int32
+	YearMax   int32
+	PagesMin  int32
+	PagesMax  int32
+	RatingMin float32
+	Subgenres bool
+}
+
+func ValidateBookFilters(v *validator.Validator, f BookFilters) {
+	v.Check(len(f.Search) <= 200, "q", "must not be more than 200 bytes long")
+	v.Check(len(f.Author) <= 300, "author", "must not be more than 300 bytes long")
+
+	if f.Language != "" {
+		v.Check(validator.Matches(f.Language, LanguageRX), "language", "must be a lowercase ISO 639 language code")
+	}
+
+	v.Check(len(f.Genres) <= 10, "genres", "must not contain more than 10 genres")
+	v.Check(len(f.GenresAny) <= 10, "genres_any", "must not contain more than 10 genres")
+	v.Check(len(f.GenresNot) <= 10, "genres_not", "must not contain more than 10 genres")
+
+	for _, genre := range f.GenresNot {
+		v.Check(!slices.Contains(f.Genres, genre), "genres_not", "must not contain genres that are also in genres")
+	}
+
+	v.Check(f.YearMin >= 0, "year_min", "must not be negative")
+	v.Check(f.YearMax >= 0, "year_max", "must not be negative")
+	if f.YearMax > 0 {
+		v.Check(f.YearMin <= f.YearMax, "year_min", "must not be greater than year_max")
+	}
+
+	v.Check(f.PagesMin >= 0, "pages_min", "must not be negative")
+	v.Check(f.PagesMax >= 0, "pages_max", "must not be negative")
+	if f.PagesMax > 0 {
+		v.Check(f.PagesMin <= f.PagesMax, "pages_min", "must not be greater than pages_max")
+	}
+
+	v.Check(f.RatingMin >= 0, "rating_min", "must not be negative")
+	v.Check(f.RatingMin <= 5, "rating_min", "must not be greater than 5")
+}
+
+// ListBooks lists the books that match bookFilters. Title matches words of
+// the title, Search the beginnings of words in the title, author, genres or
+// description. When Search is given, every book carries a Highlight of what
+// matched.
+func (b BookModel) ListBooks(bookFilters BookFilters, filters Filters) ([]*Book, Metadata, error) {
+	sortColumn, direction := filters.sortColumn(), filters.sortDirection()
+	if sortColumn == "relevance" {
+		sortColumn, direction = "ts_rank(search, query)", "DESC"
+	}
+
+	keyset, keysetArgs := filters.keyset(sortColumn, direction, "id", 16)
+
+	// The average ratings of the works are only aggregated when filtering by
+	// them, so that other listings don't pay for it.
+	ratingFilter := "$14::real = 0"
+	if bookFilters.RatingMin > 0 {
+		ratingFilter = `work_id IN (
+        SELECT e.work_id
+        FROM book_rating_stats s
+        INNER JOIN books e ON e.id = s.book_id
+        GROUP BY e.work_id
+        HAVING sum(s.ratings_sum) / NULLIF(sum(s.ratings_count), 0) >= $14
+    )`
+	}
+
 	query := fmt.Sprintf(`
-    SELECT count(*) OVER(), id, created_at, title, author, year, pages, genres, version
-    FROM books
-    WHERE (to_tsvector('simple', title) @@ plainto_tsquery('simple', $1) OR $1 = '')
-    AND (genres @> $2 OR $2 = '{}')
-    ORDER %s %s, id ASC
-    LIMIT $3 OFFSET $4`, filters.sortColumn(), filters.sortDirection())
+    SELECT %[1]s, `+bookColumns+`, %[2]s::text,
+        CASE WHEN $2 = '' THEN '' ELSE ts_headline('simple', title, query, '`+highlightAll+`') END,
+        CASE WHEN $2 = '' THEN '' ELSE ts_headline('simple', author, query, '`+highlightAll+`') END,
+        CASE WHEN $2 = '' THEN '' ELSE ts_headline('simple', description, query, '`+highlightFragment+`') END
+    FROM books, to_tsquery('simple', $2) query
+    WHERE deleted_at IS NULL
+    AND (search @@ to_tsquery('simple', $1) OR $1 = '')
+    AND (search @@ query OR $2 = '')
+    AND (CASE WHEN $15 THEN NOT EXISTS (
+        SELECT 1 FROM unnest($3::text[]) g WHERE NOT genres && genre_subtree(ARRAY[g])
+    ) ELSE genres @> $3 END OR $3 = '{}')
+    AND (genres && CASE WHEN $15 THEN genre_subtree($6) ELSE $6 END OR $6 = '{}')
+    AND (NOT genres && CASE WHEN $15 THEN genre_subtree($7) ELSE $7 END OR $7 = '{}')
+    AND (EXISTS (
+        SELECT 1 FROM books_authors ba
+        INNER JOIN authors a ON a.id = ba.author_id
+        WHERE ba.book_id = books.id AND a.name_key = lower(regexp_replace($8, '[^[:alnum:]]+', '', 'g'))
+    ) OR $8 = '')
+    AND (language = $9 OR $9 = '')
+    AND (year >= $10 OR $10 = 0)
+    AND (year <= $11 OR $11 = 0)
+    AND (pages >= $12 OR $12 = 0)
+    AND (pages <= $13 OR $13 = 0)
+    AND %[5]s
+    AND %[4]s
+    ORDER BY %[2]s %[3]s, id ASC
+    LIMIT $4 OFFSET $5`, filters.countColumn(), sortColumn, direction, keyset, ratingFilter)
+
+	for _, genres := range []*[]string{&bookFilters.Genres, &bookFilters.GenresAny, &bookFilters.GenresNot} {
+		if *genres == nil {
+			*genres = []string{}
+		}
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	args := []any{title, pq.Array(genres), filters.limit(), filters.offset()}
+	args := []any{
+		searchQuery(bookFilters.Title, ":A"),
+		searchQuery(bookFilters.Search, ":*"),
+		pq.Array(bookFilters.Genres),
+		filters.limit(),
+		filters.offset(),
+		pq.Array(bookFilters.GenresAny),
+		pq.Array(bookFilters.GenresNot),
+		bookFilters.Author,
+		bookFilters.Language,
+		bookFilters.YearMin,
+		bookFilters.YearMax,
+		bookFilters.PagesMin,
+		bookFilters.PagesMax,
+		bookFilters.RatingMin,
+		bookFilters.Subgenres,
+	}
+	args = append(args, keysetArgs...)
 
 	rows, err := b.DB.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -173,33 +556,207 @@ func (b BookModel) ListBooks(
 
 	totalRecords := 0
 	books := []*Book{}
+	keys := []cursorKey{}
 
 	for rows.Next() {
-		var book Book
-
-		err := rows.Scan(
-			&totalRecords,
-			&book.ID,
-			&book.CreatedAt,
-			&book.Title,
-			&book.Author,
-			&book.Year,
-			&book.Pages,
-			pq.Array(&book.Genres),
-			&book.Version,
+		var (
+			book      Book
+			key       cursorKey
+			highlight Highlight
 		)
+
+		dest := append([]any{&totalRecords}, scanBookDest(&book)...)
+		dest = append(dest, &key.Value, &highlight.Title, &highlight.Author, &highlight.Description)
+
+		err := rows.Scan(dest...)
 		if err != nil {
 			return nil, Metadata{}, err
 		}
 
+		if bookFilters.Search != "" {
+			book.Highlight = &highlight
+		}
+
+		key.ID = book.ID
+
 		books = append(books, &book)
+		keys = append(keys, key)
 	}
 
 	if err = rows.Close(); err != nil {
 		return nil, Metadata{}, err
 	}
 
-	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+	books, metadata := paginate(books, keys, totalRecords, filters)
 
 	return books, metadata, nil
 }
+
+// searchQuery turns the words of s into a to_tsquery expression that matches
+// all of them, each followed by suffix: with ":*" the words "hobb tolk" become
+// "hobb:* & tolk:*". Everything but letters and digits is dropped, so user
+// input can't inject tsquery operators.
+func searchQuery(s, suffix string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i := range words {
+		words[i] = strings.ToLower(words[i]) + suffix
+	}
+
+	return strings.Join(words, " & ")
+}
+
+// FindDuplicates lists books that are likely the same as the given one: those
+// with the same ISBN, or with the same title, author and year once punctuation,
+// spacing and case are folded.
+func (b BookModel) FindDuplicates(book *Book) ([]*Edition, error) {
+	query := `
+    SELECT ` + editionColumns + `
+    FROM books
+    WHERE deleted_at IS NULL AND (
+        (
+            title_key = lower(regexp_replace($1, '[^[:alnum:]]+', '', 'g'))
+            AND author_key = lower(regexp_replace($2, '[^[:alnum:]]+', '', 'g'))
+            AND year = $3
+        ) OR isbn = NULLIF($4, '')
+    )
+    ORDER BY id ASC
+    LIMIT 10`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := b.DB.QueryContext(ctx, query, book.Title, book.Author, book.Year, book.ISBN)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	duplicates := []*Edition{}
+
+	for rows.Next() {
+		var edition Edition
+
+		err := rows.Scan(scanEditionDest(&edition)...)
+		if err != nil {
+			return nil, err
+		}
+
+		duplicates = append(duplicates, &edition)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return duplicates, nil
+}
+
+// Merge moves everything that refers to the book duplicateID over to the book
+// id and deletes the duplicate. A user who has both books keeps their entry of
+// the surviving book, completed with the rating, review and read date of the
+// duplicate where it has none, and with its reading history, shelves and tags.
+// The entry takes the further of the two reading statuses and pages. A
+// soft-deleted book can't survive a merge.
+func (b BookModel) Merge(id, duplicateID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := b.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var found int
+
+	err = tx.QueryRowContext(ctx, `
+    SELECT count(*) FROM (
+        SELECT id FROM books
+        WHERE (id = $1 AND deleted_at IS NULL) OR id = $2
+        FOR UPDATE
+    ) locked`, id, duplicateID).Scan(&found)
+	if err != nil {
+		return err
+	}
+	if found != 2 {
+		return ErrRecordNotFound
+	}
+
+	// The read-throughs of the duplicate entry are moved over below, so the
+	// status change of the kept entry mustn't record another one.
+	_, err = tx.ExecContext(ctx, `SET LOCAL bookshelf.merging_books = 'on'`)
+	if err != nil {
+		return err
+	}
+
+	// pairs matches the entries of users that have both books.
+	const pairs = `
+    FROM usersBooksRelation keep, usersBooksRelation dup
+    WHERE keep.bookId = $1 AND dup.bookId = $2 AND keep.userId = dup.userId`
+
+	queries := []string{
+		`UPDATE usersBooksRelation ub
+    SET rating = COALESCE(NULLIF(keep.rating, 0), dup.rating),
+        reviewBody = CASE WHEN COALESCE(keep.reviewBody, '') = '' THEN dup.reviewBody ELSE keep.reviewBody END,
+        reviewed_at = CASE WHEN COALESCE(keep.reviewBody, '') = '' THEN dup.reviewed_at ELSE keep.reviewed_at END,
+        status = statuses.status,
+        read = statuses.status = 'read',
+        current_page = GREATEST(keep.current_page, dup.current_page),
+        read_at = CASE WHEN keep.read_at >= '1900-01-01' THEN keep.read_at ELSE dup.read_at END,
+        added_at = LEAST(keep.added_at, dup.added_at),
+        version = keep.version + 1
+    FROM usersBooksRelation keep, usersBooksRelation dup,
+        LATERAL (
+            SELECT s.status
+            FROM unnest(ARRAY['want-to-read', 'abandoned', 'reading', 'read']) WITH ORDINALITY AS s(status, rank)
+            WHERE s.status IN (keep.status, dup.status)
+            ORDER BY s.rank DESC
+            LIMIT 1
+        ) statuses
+    WHERE keep.bookId = $1 AND dup.bookId = $2 AND keep.userId = dup.userId AND ub.id = keep.id`,
+
+		`UPDATE read_throughs SET userbook_id = keep.id` + pairs + ` AND read_throughs.userbook_id = dup.id`,
+
+		`UPDATE reading_progress SET userbook_id = keep.id` + pairs + ` AND reading_progress.userbook_id = dup.id`,
+
+		`INSERT INTO shelves_books (shelf_id, userbook_id, position, shelved_at)
+    SELECT sb.shelf_id, keep.id, sb.position, sb.shelved_at
+    FROM shelves_books sb
+    INNER JOIN usersBooksRelation dup ON dup.id = sb.userbook_id AND dup.bookId = $2
+    INNER JOIN usersBooksRelation keep ON keep.userId = dup.userId AND keep.bookId = $1
+    ON CONFLICT DO NOTHING`,
+
+		`INSERT INTO userbooks_tags (userbook_id, tag_id)
+    SELECT keep.id, ut.tag_id
+    FROM userbooks_tags ut
+    INNER JOIN usersBooksRelation dup ON dup.id = ut.userbook_id AND dup.bookId = $2
+    INNER JOIN usersBooksRelation keep ON keep.userId = dup.userId AND keep.bookId = $1
+    ON CONFLICT DO NOTHING`,
+
+		`DELETE FROM usersBooksRelation
+    WHERE id IN (SELECT dup.id` + pairs + `)`,
+
+		`UPDATE usersBooksRelation SET bookId = $1, version = version + 1 WHERE bookId = $2`,
+
+		`INSERT INTO books_authors (book_id, author_id, role, position)
+    SELECT $1, author_id, role, position FROM books_authors WHERE book_id = $2
+    ON CONFLICT DO NOTHING`,
+
+		`INSERT INTO series_books (series_id, book_id, position)
+    SELECT series_id, $1, position FROM series_books WHERE book_id = $2
+    ON CONFLICT DO NOTHING`,
+
+		`DELETE FROM books WHERE id = $2`,
+	}
+
+	for _, query := range queries {
+		_, err = tx.ExecContext(ctx, query, id, duplicateID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
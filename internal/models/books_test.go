@@ -0,0 +1,369 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+func TestBookModel_GetByISBN(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := BookModel{db}
+
+	book, err := m.GetByISBN("9780261103573")
+	assert.NilError(t, err)
+	assert.Equal(t, book.Title, "The Hobbit")
+
+	_, err = m.GetByISBN("9780553103540")
+	assert.Equal(t, err, ErrRecordNotFound)
+
+	duplicate := &Book{
+		Title:  "The Hobbit",
+		Author: "J.R.R. Tolkien",
+		Year:   1937,
+		Pages:  310,
+		Genres: []string{"Fantasy"},
+		ISBN:   "9780261103573",
+	}
+
+	err = m.Insert(duplicate)
+	assert.Equal(t, err, ErrDuplicateISBN)
+}
+
+func TestBookModel_ISBNOfDeletedBook(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := BookModel{db}
+
+	assert.NilError(t, m.Delete(1))
+
+	book := &Book{
+		Title:  "The Hobbit",
+		Author: "J.R.R. Tolkien",
+		Year:   1937,
+		Pages:  310,
+		Genres: []string{"Fantasy"},
+		ISBN:   "9780261103573",
+	}
+
+	assert.NilError(t, m.Insert(book))
+	assert.Equal(t, m.Restore(1), ErrDuplicateISBN)
+}
+
+func TestBookModel_InsertLinksAuthors(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := BookModel{db}
+
+	book := &Book{
+		Title:   "Dune",
+		Author:  "Frank Herbert",
+		Year:    1965,
+		Pages:   412,
+		Genres:  []string{"Science Fiction"},
+		Authors: []BookAuthor{{AuthorID: 999, Role: RoleAuthor}},
+	}
+
+	err := m.Insert(book)
+	assert.Equal(t, err, ErrAuthorNotFound)
+
+	_, err = m.GetByTitleAuthor("Dune", "Frank Herbert")
+	assert.Equal(t, err, ErrRecordNotFound)
+
+	book.Authors = nil
+	assert.NilError(t, m.Insert(book))
+
+	authors, err := AuthorModel{db}.ListForBook(book.ID)
+	assert.NilError(t, err)
+	assert.Equal(t, len(authors), 1)
+	assert.Equal(t, authors[0].Name, "Frank Herbert")
+}
+
+func TestBookModel_FindDuplicates(t *testing.T) {
+	tests := []struct {
+		name      string
+		book      Book
+		wantCount int
+	}{
+		{name: "Same title, author and year", book: Book{Title: "the hobbit.", Author: "J.R.R. Tolkien", Year: 1890}, wantCount: 1},
+		{name: "Same ISBN", book: Book{Title: "Der Hobbit", Author: "J.R.R. Tolkien", Year: 1957, ISBN: "9780261103573"}, wantCount: 1},
+		{name: "Other year", book: Book{Title: "The Hobbit", Author: "JRR Tolkien", Year: 1937}, wantCount: 0},
+		{name: "Other book", book: Book{Title: "Dune", Author: "Frank Herbert", Year: 1965}, wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := NewTestDB(t)
+
+			m := BookModel{db}
+
+			duplicates, err := m.FindDuplicates(&tt.book)
+			assert.NilError(t, err)
+			assert.Equal(t, len(duplicates), tt.wantCount)
+		})
+	}
+}
+
+func TestBookModel_Merge(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := BookModel{db}
+
+	err := m.Merge(1, 2)
+	assert.NilError(t, err)
+
+	_, err = m.Get(2)
+	assert.Equal(t, err, ErrRecordNotFound)
+
+	userBook, err := UserBookModel{db}.GetForUser(1, 1)
+	assert.NilError(t, err)
+	assert.Equal(t, userBook.ID, int64(14))
+
+	err = m.Merge(1, 2)
+	assert.Equal(t, err, ErrRecordNotFound)
+}
+
+func TestBookModel_MergeFoldsEntries(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := BookModel{db}
+	userBooks := UserBookModel{db}
+
+	userBook := &UserBook{BookID: 1, UserID: 1, Page: 50}
+	userBook.SetStatus(StatusReading)
+	assert.NilError(t, userBooks.Insert(userBook))
+
+	err := m.Merge(1, 2)
+	assert.NilError(t, err)
+
+	merged, err := userBooks.GetForUser(1, 1)
+	assert.NilError(t, err)
+	assert.Equal(t, merged.Status, StatusRead)
+	assert.Equal(t, merged.Read, true)
+	assert.Equal(t, merged.Page, int32(50))
+	assert.Equal(t, merged.ReadAt.Year(), 2024)
+
+	readThroughs, err := ReadThroughModel{db}.ListForUserBook(merged.ID)
+	assert.NilError(t, err)
+	assert.Equal(t, len(readThroughs), 2)
+}
+
+func TestBookModel_MergeIntoDeleted(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := BookModel{db}
+
+	assert.NilError(t, m.Delete(1))
+	assert.Equal(t, m.Merge(1, 2), ErrRecordNotFound)
+
+	_, err := m.Get(2)
+	assert.NilError(t, err)
+}
+
+func TestBookModel_DeleteAndRestore(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := BookModel{db}
+
+	users, err := m.CountUsers(2)
+	assert.NilError(t, err)
+	assert.Equal(t, users, 1)
+
+	users, err = m.CountUsers(1)
+	assert.NilError(t, err)
+	assert.Equal(t, users, 0)
+
+	err = m.Delete(1)
+	assert.NilError(t, err)
+
+	_, err = m.Get(1)
+	assert.Equal(t, err, ErrRecordNotFound)
+
+	err = m.Delete(1)
+	assert.Equal(t, err, ErrRecordNotFound)
+
+	err = m.Restore(1)
+	assert.NilError(t, err)
+
+	_, err = m.Get(1)
+	assert.NilError(t, err)
+
+	err = m.Restore(1)
+	assert.Equal(t, err, ErrRecordNotFound)
+}
+
+func TestBookModel_ListBooksSearch(t *testing.T) {
+	tests := []struct {
+		name      string
+		search    string
+		wantTitle string
+		wantCount int
+	}{
+		{name: "Title prefix", search: "hobb", wantTitle: "The Hobbit", wantCount: 1},
+		{name: "Author", search: "tolkien", wantTitle: "The Hobbit", wantCount: 1},
+		{name: "Genre", search: "epic", wantTitle: "A Game Of Thrones", wantCount: 1},
+		{name: "No match", search: "dune", wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := NewTestDB(t)
+
+			m := BookModel{db}
+
+			filters := Filters{Page: 1, PageSize: 20, Sort: "relevance", SortSafeList: []string{"relevance"}}
+
+			books, _, err := m.ListBooks(BookFilters{Search: tt.search}, filters)
+			assert.NilError(t, err)
+			assert.Equal(t, len(books), tt.wantCount)
+
+			if tt.wantCount > 0 {
+				assert.Equal(t, books[0].Title, tt.wantTitle)
+				assert.Equal(t, books[0].Highlight != nil, true)
+			}
+		})
+	}
+}
+
+func TestSearchQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		suffix string
+		want   string
+	}{
+		{name: "Prefixes", input: "Hobb tolk", suffix: ":*", want: "hobb:* & tolk:*"},
+		{name: "Operators", input: "hobbit & !(ring) | 'x'", suffix: ":*", want: "hobbit:* & ring:* & x:*"},
+		{name: "Title weight", input: "The Hobbit", suffix: ":A", want: "the:A & hobbit:A"},
+		{name: "Empty", input: " - ", suffix: ":*", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, searchQuery(tt.input, tt.suffix), tt.want)
+		})
+	}
+}
+
+func TestBookModel_ListBooksCursor(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := BookModel{db}
+
+	filters := Filters{Page: 1, PageSize: 1, Sort: "title", SortSafeList: []string{"title"}}
+
+	books, metadata, err := m.ListBooks(BookFilters{}, filters)
+	assert.NilError(t, err)
+	assert.Equal(t, books[0].Title, "A Game Of Thrones")
+
+	filters.Cursor = metadata.NextCursor
+
+	books, metadata, err = m.ListBooks(BookFilters{}, filters)
+	assert.NilError(t, err)
+	assert.Equal(t, len(books), 1)
+	assert.Equal(t, books[0].Title, "The Hobbit")
+	assert.Equal(t, metadata.NextCursor, "")
+}
+
+func TestBookModel_ListBooksFilters(t *testing.T) {
+	tests := []struct {
+		name       string
+		filters    BookFilters
+		wantTitles []string
+	}{
+		{
+			name:       "Short fantasy novels from the 80s",
+			filters:    BookFilters{Genres: []string{"Fantasy"}, YearMin: 1980, YearMax: 1989, PagesMax: 400},
+			wantTitles: []string{},
+		},
+		{
+			name:       "Year and page range",
+			filters:    BookFilters{YearMin: 1980, PagesMin: 500},
+			wantTitles: []string{"A Game Of Thrones"},
+		},
+		{
+			name:       "Any genre",
+			filters:    BookFilters{GenresAny: []string{"Epic", "Childrens Literature"}},
+			wantTitles: []string{"The Hobbit", "A Game Of Thrones"},
+		},
+		{
+			name:       "Excluded genre",
+			filters:    BookFilters{GenresNot: []string{"Epic"}},
+			wantTitles: []string{"The Hobbit"},
+		},
+		{
+			name:       "Parent genre without subgenres",
+			filters:    BookFilters{Genres: []string{"Fiction"}},
+			wantTitles: []string{},
+		},
+		{
+			name:       "Parent genre with subgenres",
+			filters:    BookFilters{Genres: []string{"Fiction", "Childrens Literature"}, Subgenres: true},
+			wantTitles: []string{"The Hobbit"},
+		},
+		{
+			name:       "Excluded parent genre with subgenres",
+			filters:    BookFilters{GenresNot: []string{"Fantasy"}, Subgenres: true},
+			wantTitles: []string{},
+		},
+		{
+			name:       "Author",
+			filters:    BookFilters{Author: "J.R.R. Tolkien"},
+			wantTitles: []string{"The Hobbit"},
+		},
+		{
+			name:       "Rating",
+			filters:    BookFilters{RatingMin: 4},
+			wantTitles: []string{"A Game Of Thrones"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := NewTestDB(t)
+
+			m := BookModel{db}
+
+			filters := Filters{Page: 1, PageSize: 20, Sort: "id", SortSafeList: []string{"id"}}
+
+			books, _, err := m.ListBooks(tt.filters, filters)
+			assert.NilError(t, err)
+			assert.Equal(t, len(books), len(tt.wantTitles))
+
+			for i := range books {
+				assert.Equal(t, books[i].Title, tt.wantTitles[i])
+			}
+		})
+	}
+}
+
+func TestValidateBookFilters(t *testing.T) {
+	tests := []struct {
+		name      string
+		filters   BookFilters
+		wantError map[string]string
+	}{
+		{name: "Valid", filters: BookFilters{YearMin: 1980, YearMax: 1989, PagesMax: 300}, wantError: nil},
+		{name: "Year range", filters: BookFilters{YearMin: 1990, YearMax: 1980}, wantError: map[string]string{"year_min": "must not be greater than year_max"}},
+		{name: "Page range", filters: BookFilters{PagesMin: 300, PagesMax: 100}, wantError: map[string]string{"pages_min": "must not be greater than pages_max"}},
+		{name: "Negative pages", filters: BookFilters{PagesMax: -1}, wantError: map[string]string{"pages_max": "must not be negative"}},
+		{name: "Rating", filters: BookFilters{RatingMin: 6}, wantError: map[string]string{"rating_min": "must not be greater than 5"}},
+		{name: "Language", filters: BookFilters{Language: "English"}, wantError: map[string]string{"language": "must be a lowercase ISO 639 language code"}},
+		{
+			name:      "Genre both required and excluded",
+			filters:   BookFilters{Genres: []string{"Fantasy"}, GenresNot: []string{"Fantasy"}},
+			wantError: map[string]string{"genres_not": "must not contain genres that are also in genres"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validator.New()
+
+			ValidateBookFilters(v, tt.filters)
+			assert.DeepEqual(t, tt.wantError, v.Errors)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ExportEntry is a shelved book together with the book itself, the names of
+// the shelves it is on and the dates of its finished read-throughs.
+type ExportEntry struct {
+	BookID     int64      `json:"book_id"`
+	Title      string     `json:"title"`
+	Author     string     `json:"author"`
+	Year       int32      `json:"year"`
+	Pages      int32      `json:"pages"`
+	Genres     []string   `json:"genres"`
+	ISBN       string     `json:"isbn"`
+	Status     string     `json:"status"`
+	Rating     float32    `json:"rating"`
+	ReviewBody string     `json:"review_body"`
+	AddedAt    time.Time  `json:"added_at"`
+	ReadAt     *time.Time `json:"read_at"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	Shelves    []string   `json:"shelves"`
+	ReadDates  []string   `json:"read_dates"`
+}
+
+type ExportModel struct {
+	DB *sql.DB
+}
+
+// ForUser calls fn with every shelved book of the user, one row at a time, so
+// that libraries of any size can be written out without holding them in
+// memory. It stops at the first error returned by fn. The caller controls
+// the deadline through ctx, since a large export outlives the usual query
+// timeout.
+func (m ExportModel) ForUser(ctx context.Context, userID int64, fn func(*ExportEntry) error) error {
+	query := `
+    SELECT b.id, b.title, b.author, b.year, b.pages, b.genres, COALESCE(b.isbn, ''),
+        ub.status, ub.rating, ub.reviewBody, ub.added_at, ub.read_at, ub.reviewed_at,
+        ARRAY(
+            SELECT s.name FROM shelves_books sb
+            INNER JOIN shelves s ON s.id = sb.shelf_id
+            WHERE sb.userbook_id = ub.id
+            ORDER BY s.name
+        ),
+        ARRAY(
+            SELECT to_char(rt.finished_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') FROM read_throughs rt
+            WHERE rt.userbook_id = ub.id AND rt.finished_at IS NOT NULL
+            ORDER BY rt.finished_at
+        )
+    FROM usersBooksRelation ub
+    INNER JOIN books b ON b.id = ub.bookId
+    WHERE ub.userId = $1
+    ORDER BY ub.added_at ASC, ub.id ASC`
+
+	rows, err := m.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry ExportEntry
+		var reviewBody sql.NullString
+		var readAt, reviewedAt sql.NullTime
+
+		err := rows.Scan(
+			&entry.BookID,
+			&entry.Title,
+			&entry.Author,
+			&entry.Year,
+			&entry.Pages,
+			pq.Array(&entry.Genres),
+			&entry.ISBN,
+			&entry.Status,
+			&entry.Rating,
+			&reviewBody,
+			&entry.AddedAt,
+			&readAt,
+			&reviewedAt,
+			pq.Array(&entry.Shelves),
+			pq.Array(&entry.ReadDates),
+		)
+		if err != nil {
+			return err
+		}
+
+		entry.ReviewBody = reviewBody.String
+		entry.ReadAt = knownTime(readAt)
+		entry.ReviewedAt = knownTime(reviewedAt)
+
+		err = fn(&entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// knownTime drops the zero timestamps that entries without a read or review
+// date are stored with.
+func knownTime(t sql.NullTime) *time.Time {
+	if !t.Valid || t.Time.Year() < 1900 {
+		return nil
+	}
+
+	return &t.Time
+}
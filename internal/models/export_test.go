@@ -0,0 +1,27 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+)
+
+func TestExportModel_ForUser(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := ExportModel{db}
+
+	entries := []*ExportEntry{}
+
+	err := m.ForUser(context.Background(), 1, func(entry *ExportEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(entries), 1)
+	assert.Equal(t, entries[0].Title, "A Game Of Thrones")
+	assert.Equal(t, entries[0].Shelves[0], "Favorites")
+	assert.Equal(t, entries[0].ReadDates[0], "2024-04-10")
+}
@@ -1,24 +1,68 @@
 package models
 
 import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/svenrisse/bookshelf/internal/validator"
 )
 
+// Filters select a page of a listing. Pages are either numbered, or follow a
+// Cursor taken from the NextCursor of the previous page. Cursor pages stay
+// stable when rows are inserted in between and don't slow down when deep,
+// but have no page numbers or total.
 type Filters struct {
 	Page         int
 	PageSize     int
 	Sort         string
 	SortSafeList []string
+	Cursor       string
 }
 
 type Metadata struct {
-	CurrentPage  int `json:"current_page,omitempty"`
-	PageSize     int `json:"page_size,omitempty"`
-	FirstPage    int `json:"first_page,omitempty"`
-	LastPage     int `json:"last_page,omitempty"`
-	TotalRecords int `json:"total_records,omitempty"`
+	CurrentPage  int    `json:"current_page,omitempty"`
+	PageSize     int    `json:"page_size,omitempty"`
+	FirstPage    int    `json:"first_page,omitempty"`
+	LastPage     int    `json:"last_page,omitempty"`
+	TotalRecords int    `json:"total_records,omitempty"`
+	NextCursor   string `json:"next_cursor,omitempty"`
+}
+
+// cursor is the position after the last row of a page: the value of its sort
+// column, which is nil for NULL, and its id. Sort ties the cursor to the sort
+// it was made for.
+type cursor struct {
+	Sort  string  `json:"s"`
+	Value *string `json:"v"`
+	ID    int64   `json:"id"`
+}
+
+// cursorKey is the sort value and id of a row, scanned along with it.
+type cursorKey struct {
+	Value sql.NullString
+	ID    int64
+}
+
+func encodeCursor(c cursor) string {
+	js, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(js)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+
+	js, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+
+	err = json.Unmarshal(js, &c)
+	return c, err
 }
 
 func ValidateFilters(v *validator.Validator, f Filters) {
@@ -28,6 +72,48 @@ func ValidateFilters(v *validator.Validator, f Filters) {
 	v.Check(f.PageSize <= 100, "page_size", "must be a maximux of 100")
 
 	v.Check(validator.PermittedValue(f.Sort, f.SortSafeList...), "sort", "invalid sort value")
+
+	if f.Cursor != "" {
+		c, err := decodeCursor(f.Cursor)
+		v.Check(err == nil, "cursor", "must be a cursor returned as next_cursor")
+		v.Check(err != nil || c.Sort == f.Sort, "cursor", "must be used with the sort it was returned for")
+		v.Check(err != nil || c.Value == nil || cursorValueValid(strings.TrimPrefix(c.Sort, "-"), *c.Value),
+			"cursor", "must be a cursor returned as next_cursor")
+	}
+}
+
+var (
+	cursorIntRX   = regexp.MustCompile(`^-?[0-9]{1,9}$`)
+	cursorFloatRX = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?(e[-+]?[0-9]+)?$`)
+)
+
+// cursorValueValid reports whether a cursor value can be compared with the
+// sort column, so that a made-up cursor is turned away before it reaches the
+// query. Values of text columns are always valid.
+func cursorValueValid(column, value string) bool {
+	switch column {
+	case "id", "year", "pages", "position", "helpful_count":
+		return validator.Matches(value, cursorIntRX)
+	case "rating", "relevance":
+		return validator.Matches(value, cursorFloatRX)
+	case "added_at", "read_at", "reviewed_at":
+		for _, layout := range []string{"2006-01-02 15:04:05Z07", "2006-01-02 15:04:05Z07:00"} {
+			if _, err := time.Parse(layout, value); err == nil {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns, so that user input only
+// ever matches itself.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern returns the LIKE pattern that matches values containing s.
+func containsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
 }
 
 func (f Filters) sortColumn() string {
@@ -46,14 +132,99 @@ func (f Filters) sortDirection() string {
 	return "ASC"
 }
 
+// limit fetches one row more than a page in cursor mode, to tell whether
+// there is a next page without counting the rows.
 func (f Filters) limit() int {
+	if f.Cursor != "" {
+		return f.PageSize + 1
+	}
 	return f.PageSize
 }
 
 func (f Filters) offset() int {
+	if f.Cursor != "" {
+		return 0
+	}
 	return (f.Page - 1) * f.PageSize
 }
 
+// countColumn counts the matching rows for numbered pages. Cursor pages skip
+// the count, which needs all matching rows.
+func (f Filters) countColumn() string {
+	if f.Cursor != "" {
+		return "0"
+	}
+	return "count(*) OVER()"
+}
+
+// keyset returns the condition that selects the rows after the cursor, for a
+// listing ordered by column in direction and then by idColumn ascending. Its
+// arguments are numbered from argN and go at the end of the query arguments.
+// NULLs sort last ascending and first descending, as they do in Postgres.
+func (f Filters) keyset(column, direction, idColumn string, argN int) (string, []any) {
+	if f.Cursor == "" {
+		return "TRUE", nil
+	}
+
+	c, err := decodeCursor(f.Cursor)
+	if err != nil {
+		return "TRUE", nil
+	}
+
+	if c.Value == nil {
+		id := fmt.Sprintf("$%d", argN)
+
+		if direction == "DESC" {
+			return fmt.Sprintf("(%s IS NOT NULL OR %s > %s)", column, idColumn, id), []any{c.ID}
+		}
+		return fmt.Sprintf("(%s IS NULL AND %s > %s)", column, idColumn, id), []any{c.ID}
+	}
+
+	value, id := fmt.Sprintf("$%d", argN), fmt.Sprintf("$%d", argN+1)
+
+	if direction == "DESC" {
+		return fmt.Sprintf("(%[1]s < %[3]s OR (%[1]s = %[3]s AND %[2]s > %[4]s))", column, idColumn, value, id),
+			[]any{*c.Value, c.ID}
+	}
+	return fmt.Sprintf("(%[1]s > %[3]s OR %[1]s IS NULL OR (%[1]s = %[3]s AND %[2]s > %[4]s))", column, idColumn, value, id),
+		[]any{*c.Value, c.ID}
+}
+
+// paginate cuts the rows of a page down to its size and returns its metadata,
+// with a next cursor if there are more rows. keys holds the cursorKey of every
+// row.
+func paginate[T any](rows []T, keys []cursorKey, totalRecords int, f Filters) ([]T, Metadata) {
+	var (
+		metadata Metadata
+		more     bool
+	)
+
+	if f.Cursor != "" {
+		more = len(rows) > f.PageSize
+		if more {
+			rows, keys = rows[:f.PageSize], keys[:f.PageSize]
+		}
+
+		metadata = Metadata{PageSize: f.PageSize}
+	} else {
+		more = f.offset()+len(rows) < totalRecords
+		metadata = calculateMetadata(totalRecords, f.Page, f.PageSize)
+	}
+
+	if more && len(keys) > 0 {
+		last := keys[len(keys)-1]
+
+		c := cursor{Sort: f.Sort, ID: last.ID}
+		if last.Value.Valid {
+			c.Value = &last.Value.String
+		}
+
+		metadata.NextCursor = encodeCursor(c)
+	}
+
+	return rows, metadata
+}
+
 func calculateMetadata(totalRecords, page, pageSize int) Metadata {
 	if totalRecords == 0 {
 		return Metadata{}
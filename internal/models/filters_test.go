@@ -0,0 +1,193 @@
+package models
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+func TestFiltersKeyset(t *testing.T) {
+	title := "The Hobbit"
+
+	tests := []struct {
+		name      string
+		cursor    cursor
+		direction string
+		want      string
+		wantArgs  int
+	}{
+		{
+			name:      "Ascending",
+			cursor:    cursor{Sort: "title", Value: &title, ID: 1},
+			direction: "ASC",
+			want:      "(title > $6 OR title IS NULL OR (title = $6 AND id > $7))",
+			wantArgs:  2,
+		},
+		{
+			name:      "Descending",
+			cursor:    cursor{Sort: "-title", Value: &title, ID: 1},
+			direction: "DESC",
+			want:      "(title < $6 OR (title = $6 AND id > $7))",
+			wantArgs:  2,
+		},
+		{
+			name:      "Ascending from NULL",
+			cursor:    cursor{Sort: "title", ID: 1},
+			direction: "ASC",
+			want:      "(title IS NULL AND id > $6)",
+			wantArgs:  1,
+		},
+		{
+			name:      "Descending from NULL",
+			cursor:    cursor{Sort: "-title", ID: 1},
+			direction: "DESC",
+			want:      "(title IS NOT NULL OR id > $6)",
+			wantArgs:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Filters{Sort: tt.cursor.Sort, Cursor: encodeCursor(tt.cursor)}
+
+			condition, args := f.keyset("title", tt.direction, "id", 6)
+			assert.Equal(t, condition, tt.want)
+			assert.Equal(t, len(args), tt.wantArgs)
+		})
+	}
+
+	condition, args := Filters{}.keyset("title", "ASC", "id", 6)
+	assert.Equal(t, condition, "TRUE")
+	assert.Equal(t, len(args), 0)
+}
+
+func TestPaginate(t *testing.T) {
+	rows := []int{1, 2, 3}
+	keys := []cursorKey{
+		{Value: sql.NullString{String: "a", Valid: true}, ID: 1},
+		{Value: sql.NullString{String: "b", Valid: true}, ID: 2},
+		{Value: sql.NullString{String: "c", Valid: true}, ID: 3},
+	}
+
+	t.Run("Cursor with more rows", func(t *testing.T) {
+		f := Filters{PageSize: 2, Sort: "title", Cursor: encodeCursor(cursor{Sort: "title", ID: 9})}
+
+		page, metadata := paginate(rows, keys, 0, f)
+		assert.Equal(t, len(page), 2)
+		assert.Equal(t, metadata.TotalRecords, 0)
+
+		next, err := decodeCursor(metadata.NextCursor)
+		assert.NilError(t, err)
+		assert.Equal(t, next.ID, int64(2))
+		assert.Equal(t, *next.Value, "b")
+	})
+
+	t.Run("Cursor on the last page", func(t *testing.T) {
+		f := Filters{PageSize: 3, Sort: "title", Cursor: encodeCursor(cursor{Sort: "title", ID: 9})}
+
+		page, metadata := paginate(rows, keys, 0, f)
+		assert.Equal(t, len(page), 3)
+		assert.Equal(t, metadata.NextCursor, "")
+	})
+
+	t.Run("Numbered page", func(t *testing.T) {
+		f := Filters{Page: 1, PageSize: 3, Sort: "title"}
+
+		_, metadata := paginate(rows, keys, 7, f)
+		assert.Equal(t, metadata.LastPage, 3)
+		assert.Equal(t, metadata.NextCursor != "", true)
+	})
+}
+
+func TestValidateFiltersCursor(t *testing.T) {
+	year, notYear, bigYear := "1996", "x", "99999999999"
+	rating, hexRating := "4.5", "0x1p-2"
+	readAt, badReadAt := "2024-04-10 14:30:00+00", "yesterday"
+
+	tests := []struct {
+		name      string
+		cursor    string
+		sort      string
+		wantError map[string]string
+	}{
+		{name: "Valid cursor", cursor: encodeCursor(cursor{Sort: "title", ID: 3}), wantError: nil},
+		{name: "Garbage", cursor: "not a cursor", wantError: map[string]string{"cursor": "must be a cursor returned as next_cursor"}},
+		{
+			name:      "Other sort",
+			cursor:    encodeCursor(cursor{Sort: "-year", ID: 3}),
+			wantError: map[string]string{"cursor": "must be used with the sort it was returned for"},
+		},
+		{name: "NULL value", cursor: encodeCursor(cursor{Sort: "-year", ID: 3}), sort: "-year", wantError: nil},
+		{name: "Valid year", cursor: encodeCursor(cursor{Sort: "-year", Value: &year, ID: 3}), sort: "-year", wantError: nil},
+		{
+			name:      "Year not a number",
+			cursor:    encodeCursor(cursor{Sort: "-year", Value: &notYear, ID: 3}),
+			sort:      "-year",
+			wantError: map[string]string{"cursor": "must be a cursor returned as next_cursor"},
+		},
+		{
+			name:      "Year out of range",
+			cursor:    encodeCursor(cursor{Sort: "-year", Value: &bigYear, ID: 3}),
+			sort:      "-year",
+			wantError: map[string]string{"cursor": "must be a cursor returned as next_cursor"},
+		},
+		{name: "Valid rating", cursor: encodeCursor(cursor{Sort: "rating", Value: &rating, ID: 3}), sort: "rating", wantError: nil},
+		{
+			name:      "Rating in hex",
+			cursor:    encodeCursor(cursor{Sort: "rating", Value: &hexRating, ID: 3}),
+			sort:      "rating",
+			wantError: map[string]string{"cursor": "must be a cursor returned as next_cursor"},
+		},
+		{
+			name:   "Valid read_at",
+			cursor: encodeCursor(cursor{Sort: "-read_at", Value: &readAt, ID: 3}),
+			sort:   "-read_at",
+		},
+		{
+			name:      "Invalid read_at",
+			cursor:    encodeCursor(cursor{Sort: "-read_at", Value: &badReadAt, ID: 3}),
+			sort:      "-read_at",
+			wantError: map[string]string{"cursor": "must be a cursor returned as next_cursor"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validator.New()
+
+			sort := tt.sort
+			if sort == "" {
+				sort = "title"
+			}
+
+			f := Filters{
+				Page: 1, PageSize: 20, Sort: sort,
+				SortSafeList: []string{"title", "-year", "rating", "-read_at"}, Cursor: tt.cursor,
+			}
+
+			ValidateFilters(v, f)
+			assert.DeepEqual(t, tt.wantError, v.Errors)
+		})
+	}
+}
+
+func TestContainsPattern(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "Plain", s: "tolkien", want: "%tolkien%"},
+		{name: "Percent", s: "100%", want: `%100\%%`},
+		{name: "Underscore", s: "a_b", want: `%a\_b%`},
+		{name: "Backslash", s: `a\b`, want: `%a\\b%`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, containsPattern(tt.s), tt.want)
+		})
+	}
+}
@@ -0,0 +1,492 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lib/pq"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+var (
+	ErrDuplicateGenre = errors.New("duplicate genre")
+	ErrGenreNotFound  = errors.New("genre not found")
+	ErrGenreCycle     = errors.New("genre cycle")
+)
+
+// Genre is part of the controlled genre vocabulary. Books store the names of
+// their genres; aliases are other spellings that are turned into the name when
+// a book is saved. BookCount counts the books with this very genre,
+// TotalBookCount also those with one of its subgenres.
+type Genre struct {
+	ID             int64     `json:"id"               example:"3"`
+	Name           string    `json:"name"             example:"Science Fiction"`
+	ParentID       int64     `json:"parent_id"        example:"1"`
+	Aliases        []string  `json:"aliases"          example:"Sci-Fi,SF"`
+	BookCount      int       `json:"book_count"       example:"12"`
+	TotalBookCount int       `json:"total_book_count" example:"40"`
+	Children       []*Genre  `json:"children,omitempty"`
+	CreatedAt      time.Time `json:"-"`
+	Version        int32     `json:"-"`
+}
+
+// GenreVocabulary maps the folded names and aliases of all genres to their
+// names.
+type GenreVocabulary map[string]string
+
+// genreKey folds punctuation, spacing and case the same way the name_key and
+// alias_key columns do.
+func genreKey(name string) string {
+	return strings.ToLower(strings.Map(func(r rune) rune {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return -1
+		}
+		return r
+	}, name))
+}
+
+// Canonical returns the name of the genre that name or one of its aliases
+// spells, and whether there is such a genre.
+func (gv GenreVocabulary) Canonical(name string) (string, bool) {
+	canonical, ok := gv[genreKey(name)]
+	return canonical, ok
+}
+
+func ValidateGenre(v *validator.Validator, genre *Genre) {
+	v.Check(genreKey(genre.Name) != "", "name", "must be provided")
+	v.Check(len(genre.Name) <= 100, "name", "must not be more than 100 bytes long")
+
+	v.Check(genre.ParentID >= 0, "parent_id", "must be a positive integer")
+	v.Check(genre.ParentID != genre.ID || genre.ID == 0, "parent_id", "must not be the genre itself")
+
+	v.Check(len(genre.Aliases) <= 20, "aliases", "must not contain more than 20 aliases")
+
+	keys := []string{genreKey(genre.Name)}
+	for _, alias := range genre.Aliases {
+		v.Check(genreKey(alias) != "", "aliases", "must not contain blank values")
+		v.Check(len(alias) <= 100, "aliases", "must not contain values more than 100 bytes long")
+		keys = append(keys, genreKey(alias))
+	}
+	v.Check(validator.Unique(keys), "aliases", "must not repeat the name or each other")
+}
+
+type GenreModel struct {
+	DB *sql.DB
+}
+
+// Vocabulary loads the names and aliases of all genres. A name wins over an
+// alias with the same folded spelling.
+func (m GenreModel) Vocabulary() (GenreVocabulary, error) {
+	query := `
+    SELECT name_key, name, false
+    FROM genres
+    UNION ALL
+    SELECT a.alias_key, g.name, true
+    FROM genre_aliases a
+    INNER JOIN genres g ON g.id = a.genre_id`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	vocabulary := GenreVocabulary{}
+
+	for rows.Next() {
+		var (
+			key, name string
+			isAlias   bool
+		)
+
+		err := rows.Scan(&key, &name, &isAlias)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, exists := vocabulary[key]; exists && isAlias {
+			continue
+		}
+		vocabulary[key] = name
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return vocabulary, nil
+}
+
+// genreColumns are the columns scanned by scanGenreDest, in the same order.
+const genreColumns = `
+    g.id, g.name, COALESCE(g.parent_id, 0),
+    ARRAY(SELECT a.alias FROM genre_aliases a WHERE a.genre_id = g.id ORDER BY a.alias),
+    (SELECT count(*) FROM books b WHERE b.genres @> ARRAY[g.name] AND b.deleted_at IS NULL),
+    (SELECT count(*) FROM books b WHERE b.genres && genre_subtree(ARRAY[g.name]) AND b.deleted_at IS NULL),
+    g.created_at, g.version`
+
+func scanGenreDest(genre *Genre) []any {
+	return []any{
+		&genre.ID,
+		&genre.Name,
+		&genre.ParentID,
+		pq.Array(&genre.Aliases),
+		&genre.BookCount,
+		&genre.TotalBookCount,
+		&genre.CreatedAt,
+		&genre.Version,
+	}
+}
+
+func (m GenreModel) Get(id int64) (*Genre, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+    SELECT ` + genreColumns + `
+    FROM genres g
+    WHERE g.id = $1`
+
+	var genre Genre
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, id).Scan(scanGenreDest(&genre)...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return &genre, nil
+}
+
+// Tree returns the top level genres with their subgenres as children, each
+// level sorted by name.
+func (m GenreModel) Tree() ([]*Genre, error) {
+	query := `
+    SELECT ` + genreColumns + `
+    FROM genres g
+    ORDER BY g.name ASC, g.id ASC`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := []*Genre{}
+
+	for rows.Next() {
+		var genre Genre
+
+		err := rows.Scan(scanGenreDest(&genre)...)
+		if err != nil {
+			return nil, err
+		}
+
+		genres = append(genres, &genre)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return buildGenreTree(genres), nil
+}
+
+// buildGenreTree hangs every genre below its parent and returns the roots,
+// keeping the order of genres within each level.
+func buildGenreTree(genres []*Genre) []*Genre {
+	byID := make(map[int64]*Genre, len(genres))
+	for _, genre := range genres {
+		byID[genre.ID] = genre
+	}
+
+	roots := []*Genre{}
+
+	for _, genre := range genres {
+		parent, ok := byID[genre.ParentID]
+		if !ok {
+			roots = append(roots, genre)
+			continue
+		}
+		parent.Children = append(parent.Children, genre)
+	}
+
+	return roots
+}
+
+// Insert adds the genre and its aliases.
+func (m GenreModel) Insert(genre *Genre) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+    INSERT INTO genres (name, parent_id)
+    VALUES ($1, NULLIF($2, 0))
+    RETURNING id, created_at, version`
+
+	err = tx.QueryRowContext(ctx, query, genre.Name, genre.ParentID).Scan(&genre.ID, &genre.CreatedAt, &genre.Version)
+	if err != nil {
+		return genreError(err)
+	}
+
+	err = replaceGenreAliases(ctx, tx, genre)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update saves the genre and replaces its aliases. When the genre is renamed,
+// the books with the old name get the new one.
+func (m GenreModel) Update(genre *Genre) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The new parent must not be the genre itself or one of its subgenres.
+	query := `
+    WITH RECURSIVE ancestors AS (
+        SELECT id, parent_id FROM genres WHERE id = $1
+        UNION
+        SELECT g.id, g.parent_id FROM genres g INNER JOIN ancestors a ON g.id = a.parent_id
+    )
+    SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2)`
+
+	var cycle bool
+
+	err = tx.QueryRowContext(ctx, query, genre.ParentID, genre.ID).Scan(&cycle)
+	if err != nil {
+		return err
+	}
+
+	if cycle {
+		return ErrGenreCycle
+	}
+
+	query = `
+    UPDATE genres g
+    SET name = $1, parent_id = NULLIF($2, 0), version = g.version + 1
+    FROM genres prev
+    WHERE g.id = $3 AND g.version = $4 AND prev.id = g.id
+    RETURNING prev.name, g.version`
+
+	args := []any{genre.Name, genre.ParentID, genre.ID, genre.Version}
+
+	var oldName string
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&oldName, &genre.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrEditConflict
+		}
+		return genreError(err)
+	}
+
+	err = replaceGenreAliases(ctx, tx, genre)
+	if err != nil {
+		return err
+	}
+
+	if oldName != genre.Name {
+		query = `
+        UPDATE books
+        SET genres = array_replace(genres, $1, $2), version = version + 1
+        WHERE genres @> ARRAY[$1]`
+
+		_, err = tx.ExecContext(ctx, query, oldName, genre.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Merge folds the duplicate genre into the genre with the given id: books
+// with the duplicate get the genre instead, the duplicate's name and aliases
+// become aliases of the genre and its subgenres move below the genre. When
+// the genre is itself below the duplicate, it takes the duplicate's place.
+func (m GenreModel) Merge(id, duplicateID int64) error {
+	if id < 1 || duplicateID < 1 {
+		return ErrRecordNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+    SELECT g.name, d.name, COALESCE(d.parent_id, 0)
+    FROM genres g, genres d
+    WHERE g.id = $1 AND d.id = $2
+    FOR UPDATE`
+
+	var (
+		name, duplicateName string
+		duplicateParentID   int64
+	)
+
+	err = tx.QueryRowContext(ctx, query, id, duplicateID).Scan(&name, &duplicateName, &duplicateParentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrRecordNotFound
+		}
+		return err
+	}
+
+	query = `
+    WITH RECURSIVE ancestors AS (
+        SELECT id, parent_id FROM genres WHERE id = $1
+        UNION
+        SELECT g.id, g.parent_id FROM genres g INNER JOIN ancestors a ON g.id = a.parent_id
+    )
+    UPDATE genres
+    SET parent_id = NULLIF($3, 0), version = version + 1
+    WHERE id = $1 AND EXISTS (SELECT 1 FROM ancestors WHERE id = $2)`
+
+	_, err = tx.ExecContext(ctx, query, id, duplicateID, duplicateParentID)
+	if err != nil {
+		return err
+	}
+
+	query = `
+    UPDATE genres
+    SET parent_id = $1, version = version + 1
+    WHERE parent_id = $2 AND id <> $1`
+
+	_, err = tx.ExecContext(ctx, query, id, duplicateID)
+	if err != nil {
+		return err
+	}
+
+	query = `
+    UPDATE genre_aliases
+    SET genre_id = $1
+    WHERE genre_id = $2`
+
+	_, err = tx.ExecContext(ctx, query, id, duplicateID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM genres WHERE id = $1`, duplicateID)
+	if err != nil {
+		return err
+	}
+
+	query = `
+    INSERT INTO genre_aliases (alias, genre_id)
+    VALUES ($1, $2)
+    ON CONFLICT (alias_key) DO NOTHING`
+
+	_, err = tx.ExecContext(ctx, query, duplicateName, id)
+	if err != nil {
+		return err
+	}
+
+	// Books that had both genres keep the genre once, where it came first.
+	query = `
+    UPDATE books
+    SET genres = ARRAY(
+            SELECT u.genre
+            FROM unnest(array_replace(genres, $1, $2)) WITH ORDINALITY AS u(genre, n)
+            GROUP BY u.genre
+            ORDER BY min(u.n)
+        ),
+        version = version + 1
+    WHERE genres @> ARRAY[$1]`
+
+	_, err = tx.ExecContext(ctx, query, duplicateName, name)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// replaceGenreAliases makes the aliases of the genre exactly genre.Aliases.
+// Names and aliases share one namespace, so neither may spell another genre.
+func replaceGenreAliases(ctx context.Context, tx *sql.Tx, genre *Genre) error {
+	query := `
+    SELECT EXISTS (
+        SELECT 1 FROM genre_aliases
+        WHERE alias_key = lower(regexp_replace($1, '[^[:alnum:]]+', '', 'g')) AND genre_id <> $2
+    ) OR EXISTS (
+        SELECT 1 FROM genres
+        WHERE name_key IN (SELECT lower(regexp_replace(a, '[^[:alnum:]]+', '', 'g')) FROM unnest($3::text[]) a)
+    )`
+
+	if genre.Aliases == nil {
+		genre.Aliases = []string{}
+	}
+
+	var taken bool
+
+	err := tx.QueryRowContext(ctx, query, genre.Name, genre.ID, pq.Array(genre.Aliases)).Scan(&taken)
+	if err != nil {
+		return err
+	}
+
+	if taken {
+		return ErrDuplicateGenre
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM genre_aliases WHERE genre_id = $1`, genre.ID)
+	if err != nil {
+		return err
+	}
+
+	query = `
+    INSERT INTO genre_aliases (alias, genre_id)
+    SELECT a, $2 FROM unnest($1::text[]) a`
+
+	_, err = tx.ExecContext(ctx, query, pq.Array(genre.Aliases), genre.ID)
+	if err != nil {
+		return genreError(err)
+	}
+
+	return nil
+}
+
+func genreError(err error) error {
+	switch {
+	case strings.Contains(err.Error(), "genres_name_key_key"),
+		strings.Contains(err.Error(), "genre_aliases_alias_key_key"):
+		return ErrDuplicateGenre
+	case strings.Contains(err.Error(), "genres_parent_id_fkey"):
+		return ErrGenreNotFound
+	default:
+		return err
+	}
+}
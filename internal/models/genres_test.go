@@ -0,0 +1,164 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+func TestGenreModel_Vocabulary(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := GenreModel{db}
+
+	vocabulary, err := m.Vocabulary()
+	assert.NilError(t, err)
+
+	tests := []struct {
+		name      string
+		genre     string
+		wantName  string
+		wantKnown bool
+	}{
+		{name: "Name", genre: "Fantasy", wantName: "Fantasy", wantKnown: true},
+		{name: "Folded name", genre: "science-fiction", wantName: "Science Fiction", wantKnown: true},
+		{name: "Alias", genre: "SciFi", wantName: "Science Fiction", wantKnown: true},
+		{name: "Unknown", genre: "Cookbooks", wantName: "", wantKnown: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, known := vocabulary.Canonical(tt.genre)
+			assert.Equal(t, name, tt.wantName)
+			assert.Equal(t, known, tt.wantKnown)
+		})
+	}
+}
+
+func TestGenreModel_Tree(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := GenreModel{db}
+
+	roots, err := m.Tree()
+	assert.NilError(t, err)
+	assert.Equal(t, len(roots), 3)
+
+	fiction := roots[1]
+	assert.Equal(t, fiction.Name, "Fiction")
+	assert.Equal(t, fiction.BookCount, 0)
+	assert.Equal(t, fiction.TotalBookCount, 2)
+	assert.Equal(t, len(fiction.Children), 2)
+
+	fantasy := fiction.Children[0]
+	assert.Equal(t, fantasy.Name, "Fantasy")
+	assert.Equal(t, fantasy.BookCount, 2)
+	assert.Equal(t, fantasy.Children[0].Name, "Epic")
+	assert.Equal(t, fantasy.Children[0].BookCount, 1)
+
+	scienceFiction := fiction.Children[1]
+	assert.Equal(t, len(scienceFiction.Aliases), 2)
+	assert.Equal(t, scienceFiction.Aliases[0], "Sci-Fi")
+}
+
+func TestGenreModel_Update(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := GenreModel{db}
+	books := BookModel{db}
+
+	roots, err := m.Tree()
+	assert.NilError(t, err)
+
+	fantasy := roots[1].Children[0]
+	epic := fantasy.Children[0]
+
+	epic.Name = "Epic Fantasy"
+	epic.Aliases = []string{"Epic"}
+	assert.NilError(t, m.Update(epic))
+
+	book, err := books.Get(2)
+	assert.NilError(t, err)
+	assert.Equal(t, book.Genres[1], "Epic Fantasy")
+
+	fantasy.ParentID = epic.ID
+	assert.Equal(t, m.Update(fantasy), ErrGenreCycle)
+
+	fantasy.ParentID = roots[1].ID
+	fantasy.Aliases = []string{"Epic"}
+	assert.Equal(t, m.Update(fantasy), ErrDuplicateGenre)
+}
+
+func TestGenreModel_Merge(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := GenreModel{db}
+	books := BookModel{db}
+
+	roots, err := m.Tree()
+	assert.NilError(t, err)
+
+	fiction := roots[1]
+	fantasy := fiction.Children[0]
+	epic := fantasy.Children[0]
+	scienceFiction := fiction.Children[1]
+
+	assert.NilError(t, m.Merge(fantasy.ID, epic.ID))
+
+	book, err := books.Get(2)
+	assert.NilError(t, err)
+	assert.Equal(t, len(book.Genres), 1)
+	assert.Equal(t, book.Genres[0], "Fantasy")
+
+	_, err = m.Get(epic.ID)
+	assert.Equal(t, err, ErrRecordNotFound)
+
+	vocabulary, err := m.Vocabulary()
+	assert.NilError(t, err)
+
+	name, _ := vocabulary.Canonical("Epic")
+	assert.Equal(t, name, "Fantasy")
+
+	// Fantasy is below Fiction, so it takes Fiction's place at the top.
+	assert.NilError(t, m.Merge(fantasy.ID, fiction.ID))
+
+	merged, err := m.Get(fantasy.ID)
+	assert.NilError(t, err)
+	assert.Equal(t, merged.ParentID, int64(0))
+
+	moved, err := m.Get(scienceFiction.ID)
+	assert.NilError(t, err)
+	assert.Equal(t, moved.ParentID, fantasy.ID)
+
+	assert.Equal(t, m.Merge(fantasy.ID, epic.ID), ErrRecordNotFound)
+}
+
+func TestValidateBookGenres(t *testing.T) {
+	vocabulary := GenreVocabulary{"fantasy": "Fantasy", "scifi": "Science Fiction", "sciencefiction": "Science Fiction"}
+
+	tests := []struct {
+		name       string
+		genres     []string
+		wantGenres []string
+		wantError  map[string]string
+	}{
+		{name: "Aliases", genres: []string{"fantasy", "Sci-Fi"}, wantGenres: []string{"Fantasy", "Science Fiction"}, wantError: nil},
+		{name: "Unknown", genres: []string{"Cookbooks"}, wantGenres: []string{"Cookbooks"}, wantError: map[string]string{"genres": "must only contain known genres"}},
+		{name: "Alias of a given genre", genres: []string{"SciFi", "Science Fiction"}, wantGenres: []string{"Science Fiction", "Science Fiction"}, wantError: map[string]string{"genres": "must not contain duplicate values"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := &Book{Title: "Dune", Author: "Frank Herbert", Year: 1965, Pages: 412, Genres: tt.genres}
+
+			v := validator.New()
+			ValidateBook(v, book, vocabulary)
+			assert.DeepEqual(t, tt.wantError, v.Errors)
+
+			for i := range tt.wantGenres {
+				assert.Equal(t, book.Genres[i], tt.wantGenres[i])
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+var ErrDuplicateGoal = errors.New("duplicate goal")
+
+const (
+	GoalStatusUpcoming  = "upcoming"
+	GoalStatusOnTrack   = "on-track"
+	GoalStatusBehind    = "behind"
+	GoalStatusCompleted = "completed"
+	GoalStatusMissed    = "missed"
+)
+
+// Goal is a yearly reading challenge. Either target may be zero, but not both.
+type Goal struct {
+	ID          int64        `json:"-"`
+	UserID      int64        `json:"-"`
+	Year        int          `json:"year"         example:"2026"`
+	TargetBooks int          `json:"target_books" example:"40"`
+	TargetPages int          `json:"target_pages" example:"12000"`
+	Progress    GoalProgress `json:"progress"`
+	CreatedAt   time.Time    `json:"-"`
+	Version     int32        `json:"-"`
+}
+
+// GoalProgress is computed from the read-throughs finished during the year of
+// the goal. The Expected values are what the reader should have reached by
+// now to finish the goal in time.
+type GoalProgress struct {
+	BooksRead     int     `json:"books_read"`
+	PagesRead     int     `json:"pages_read"`
+	ExpectedBooks float64 `json:"expected_books"`
+	ExpectedPages float64 `json:"expected_pages"`
+	Status        string  `json:"status"`
+}
+
+func ValidateGoal(v *validator.Validator, goal *Goal) {
+	v.Check(goal.Year >= 1900, "year", "must be greater than 1900")
+	v.Check(goal.Year <= time.Now().Year()+1, "year", "must not be more than a year in the future")
+
+	v.Check(goal.TargetBooks >= 0, "target_books", "must not be negative")
+	v.Check(goal.TargetBooks <= 10_000, "target_books", "must not be more than 10000")
+	v.Check(goal.TargetPages >= 0, "target_pages", "must not be negative")
+	v.Check(goal.TargetPages <= 10_000_000, "target_pages", "must not be more than 10 million")
+
+	v.Check(goal.TargetBooks > 0 || goal.TargetPages > 0, "target_books", "either target_books or target_pages must be provided")
+}
+
+// computeStatus derives the expected progress and the on-track indicator from
+// the share of the goal's year that has passed at now.
+func (goal *Goal) computeStatus(now time.Time) {
+	start := time.Date(goal.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+
+	elapsed := float64(now.Sub(start)) / float64(end.Sub(start))
+	elapsed = min(max(elapsed, 0), 1)
+
+	p := &goal.Progress
+
+	p.ExpectedBooks = float64(goal.TargetBooks) * elapsed
+	p.ExpectedPages = float64(goal.TargetPages) * elapsed
+
+	completed := p.BooksRead >= goal.TargetBooks && p.PagesRead >= goal.TargetPages
+	onTrack := float64(p.BooksRead) >= p.ExpectedBooks && float64(p.PagesRead) >= p.ExpectedPages
+
+	switch {
+	case completed:
+		p.Status = GoalStatusCompleted
+	case now.Before(start):
+		p.Status = GoalStatusUpcoming
+	case !now.Before(end):
+		p.Status = GoalStatusMissed
+	case onTrack:
+		p.Status = GoalStatusOnTrack
+	default:
+		p.Status = GoalStatusBehind
+	}
+}
+
+type GoalModel struct {
+	DB *sql.DB
+}
+
+func (m GoalModel) Insert(goal *Goal) error {
+	query := `
+    INSERT INTO goals (user_id, year, target_books, target_pages)
+    VALUES ($1, $2, $3, $4)
+    RETURNING id, created_at, version`
+
+	args := []any{goal.UserID, goal.Year, goal.TargetBooks, goal.TargetPages}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&goal.ID, &goal.CreatedAt, &goal.Version)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "goals_user_id_year_key"):
+			return ErrDuplicateGoal
+		default:
+			return err
+		}
+	}
+
+	return m.loadProgress(goal)
+}
+
+const goalQuery = `
+    SELECT g.id, g.user_id, g.year, g.target_books, g.target_pages, g.created_at, g.version, p.books, p.pages
+    FROM goals g
+    CROSS JOIN LATERAL (
+        SELECT count(rt.id), COALESCE(sum(b.pages), 0)
+        FROM read_throughs rt
+        INNER JOIN usersBooksRelation ub ON ub.id = rt.userbook_id
+        INNER JOIN books b ON b.id = ub.bookId
+        WHERE ub.userId = g.user_id
+        AND rt.finished_at >= make_timestamptz(g.year, 1, 1, 0, 0, 0, 'UTC')
+        AND rt.finished_at < make_timestamptz(g.year + 1, 1, 1, 0, 0, 0, 'UTC')
+    ) p(books, pages)`
+
+func (m GoalModel) GetForUser(userID int64, year int) (*Goal, error) {
+	query := goalQuery + `
+    WHERE g.user_id = $1 AND g.year = $2`
+
+	var goal Goal
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, userID, year).Scan(
+		&goal.ID,
+		&goal.UserID,
+		&goal.Year,
+		&goal.TargetBooks,
+		&goal.TargetPages,
+		&goal.CreatedAt,
+		&goal.Version,
+		&goal.Progress.BooksRead,
+		&goal.Progress.PagesRead,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	goal.computeStatus(time.Now())
+
+	return &goal, nil
+}
+
+func (m GoalModel) ListForUser(userID int64) ([]*Goal, error) {
+	query := goalQuery + `
+    WHERE g.user_id = $1
+    ORDER BY g.year DESC`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	goals := []*Goal{}
+
+	for rows.Next() {
+		var goal Goal
+
+		err := rows.Scan(
+			&goal.ID,
+			&goal.UserID,
+			&goal.Year,
+			&goal.TargetBooks,
+			&goal.TargetPages,
+			&goal.CreatedAt,
+			&goal.Version,
+			&goal.Progress.BooksRead,
+			&goal.Progress.PagesRead,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		goal.computeStatus(now)
+
+		goals = append(goals, &goal)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return goals, nil
+}
+
+func (m GoalModel) Update(goal *Goal) error {
+	query := `
+    UPDATE goals
+    SET target_books = $1, target_pages = $2, version = version + 1
+    WHERE id = $3 AND version = $4
+    RETURNING version`
+
+	args := []any{goal.TargetBooks, goal.TargetPages, goal.ID, goal.Version}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&goal.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrEditConflict
+		}
+		return err
+	}
+
+	goal.computeStatus(time.Now())
+
+	return nil
+}
+
+func (m GoalModel) DeleteForUser(userID int64, year int) error {
+	query := `
+    DELETE FROM goals
+    WHERE user_id = $1 AND year = $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, userID, year)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (m GoalModel) loadProgress(goal *Goal) error {
+	stored, err := m.GetForUser(goal.UserID, goal.Year)
+	if err != nil {
+		return err
+	}
+
+	goal.Progress = stored.Progress
+
+	return nil
+}
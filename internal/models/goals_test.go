@@ -0,0 +1,112 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+func TestGoalModel_GetForUser(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := GoalModel{db}
+
+	goal := &Goal{UserID: 1, Year: 2024, TargetBooks: 10}
+	err := m.Insert(goal)
+	assert.NilError(t, err)
+
+	got, err := m.GetForUser(1, 2024)
+	assert.NilError(t, err)
+
+	assert.Equal(t, got.Progress.BooksRead, 1)
+	assert.Equal(t, got.Progress.PagesRead, 700)
+	assert.Equal(t, got.Progress.Status, GoalStatusMissed)
+
+	err = m.Insert(&Goal{UserID: 1, Year: 2024, TargetPages: 5000})
+	assert.Equal(t, err, ErrDuplicateGoal)
+}
+
+func TestGoalComputeStatus(t *testing.T) {
+	// halfway through 2025, which isn't a leap year
+	now := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		goal       Goal
+		wantStatus string
+	}{
+		{
+			name:       "On track",
+			goal:       Goal{Year: 2025, TargetBooks: 40, Progress: GoalProgress{BooksRead: 21}},
+			wantStatus: GoalStatusOnTrack,
+		},
+		{
+			name:       "Behind",
+			goal:       Goal{Year: 2025, TargetBooks: 40, Progress: GoalProgress{BooksRead: 12}},
+			wantStatus: GoalStatusBehind,
+		},
+		{
+			name:       "Behind on pages",
+			goal:       Goal{Year: 2025, TargetBooks: 10, TargetPages: 12000, Progress: GoalProgress{BooksRead: 8, PagesRead: 2400}},
+			wantStatus: GoalStatusBehind,
+		},
+		{
+			name:       "Completed",
+			goal:       Goal{Year: 2025, TargetPages: 12000, Progress: GoalProgress{PagesRead: 12001}},
+			wantStatus: GoalStatusCompleted,
+		},
+		{
+			name:       "Missed",
+			goal:       Goal{Year: 2024, TargetBooks: 40, Progress: GoalProgress{BooksRead: 39}},
+			wantStatus: GoalStatusMissed,
+		},
+		{
+			name:       "Upcoming",
+			goal:       Goal{Year: 2026, TargetBooks: 40},
+			wantStatus: GoalStatusUpcoming,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.goal.computeStatus(now)
+
+			assert.Equal(t, tt.goal.Progress.Status, tt.wantStatus)
+		})
+	}
+}
+
+func TestValidateGoal(t *testing.T) {
+	tests := []struct {
+		name      string
+		goal      Goal
+		wantError map[string]string
+	}{
+		{
+			name:      "Valid books goal",
+			goal:      Goal{Year: 2025, TargetBooks: 40},
+			wantError: nil,
+		},
+		{
+			name:      "Missing target",
+			goal:      Goal{Year: 2025},
+			wantError: map[string]string{"target_books": "either target_books or target_pages must be provided"},
+		},
+		{
+			name:      "Negative pages",
+			goal:      Goal{Year: 2025, TargetBooks: 40, TargetPages: -1},
+			wantError: map[string]string{"target_pages": "must not be negative"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validator.New()
+
+			ValidateGoal(v, &tt.goal)
+			assert.DeepEqual(t, tt.wantError, v.Errors)
+		})
+	}
+}
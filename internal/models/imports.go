@@ -0,0 +1,243 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrDuplicateImport = errors.New("duplicate import")
+
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+const ImportSourceGoodreads = "goodreads"
+
+// importStaleAfter is how long a pending or running import may go without
+// progress before it is taken to be abandoned and may be run again.
+const importStaleAfter = 15 * time.Minute
+
+// Import is a bulk import of a user's library from another service. The file
+// is identified by its SHA-256 hash, so uploading the same file twice yields
+// the existing import instead of a second run, unless that import failed or
+// was abandoned.
+type Import struct {
+	ID         int64           `json:"id"`
+	UserID     int64           `json:"-"`
+	FileHash   []byte          `json:"-"`
+	Source     string          `json:"source"      example:"goodreads"`
+	Status     string          `json:"status"      example:"running"`
+	TotalRows  int             `json:"total_rows"  example:"412"`
+	Processed  int             `json:"processed"   example:"120"`
+	Matched    int             `json:"matched"     example:"97"`
+	Created    int             `json:"created"     example:"21"`
+	Failed     int             `json:"failed"      example:"2"`
+	Failures   []ImportFailure `json:"failures"`
+	CreatedAt  time.Time       `json:"created_at"`
+	FinishedAt *time.Time      `json:"finished_at"`
+}
+
+// ImportFailure records why a single row of an import was skipped.
+type ImportFailure struct {
+	Row    int    `json:"row"    example:"17"`
+	Title  string `json:"title"  example:"The Hobbit"`
+	Reason string `json:"reason" example:"year: must be provided"`
+}
+
+type ImportModel struct {
+	DB *sql.DB
+}
+
+func (m ImportModel) Insert(imp *Import) error {
+	query := `
+    INSERT INTO imports (user_id, file_hash, source, total_rows)
+    VALUES ($1, $2, $3, $4)
+    RETURNING id, status, created_at`
+
+	args := []any{imp.UserID, imp.FileHash, imp.Source, imp.TotalRows}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&imp.ID, &imp.Status, &imp.CreatedAt)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "imports_user_id_file_hash_key"):
+			return ErrDuplicateImport
+		default:
+			return err
+		}
+	}
+
+	imp.Failures = []ImportFailure{}
+
+	return nil
+}
+
+const importQuery = `
+    SELECT id, user_id, file_hash, source, status, total_rows, processed, matched, created, failed, created_at, finished_at
+    FROM imports`
+
+func (m ImportModel) GetForUser(userID, id int64) (*Import, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	return m.get(importQuery+`
+    WHERE user_id = $1 AND id = $2`, userID, id)
+}
+
+func (m ImportModel) GetForUserByHash(userID int64, hash []byte) (*Import, error) {
+	return m.get(importQuery+`
+    WHERE user_id = $1 AND file_hash = $2`, userID, hash)
+}
+
+func (m ImportModel) get(query string, args ...any) (*Import, error) {
+	var imp Import
+	var finishedAt sql.NullTime
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(
+		&imp.ID,
+		&imp.UserID,
+		&imp.FileHash,
+		&imp.Source,
+		&imp.Status,
+		&imp.TotalRows,
+		&imp.Processed,
+		&imp.Matched,
+		&imp.Created,
+		&imp.Failed,
+		&imp.CreatedAt,
+		&finishedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	if finishedAt.Valid {
+		imp.FinishedAt = &finishedAt.Time
+	}
+
+	query = `
+    SELECT row_number, title, reason
+    FROM import_failures
+    WHERE import_id = $1
+    ORDER BY row_number`
+
+	rows, err := m.DB.QueryContext(ctx, query, imp.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	imp.Failures = []ImportFailure{}
+
+	for rows.Next() {
+		var failure ImportFailure
+
+		err := rows.Scan(&failure.Row, &failure.Title, &failure.Reason)
+		if err != nil {
+			return nil, err
+		}
+
+		imp.Failures = append(imp.Failures, failure)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &imp, nil
+}
+
+// Update stores the status and the counters of a running import. Imports are
+// only ever written by the goroutine processing them, so there is no version
+// check.
+func (m ImportModel) Update(imp *Import) error {
+	query := `
+    UPDATE imports
+    SET status = $1, processed = $2, matched = $3, created = $4, failed = $5, finished_at = $6, updated_at = NOW()
+    WHERE id = $7`
+
+	args := []any{
+		imp.Status,
+		imp.Processed,
+		imp.Matched,
+		imp.Created,
+		imp.Failed,
+		imp.FinishedAt,
+		imp.ID,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.DB.ExecContext(ctx, query, args...)
+	return err
+}
+
+// Restart resets a failed or abandoned import to pending so that it can be
+// run again with the given number of rows. ErrEditConflict is returned when the
+// import is still running or has completed.
+func (m ImportModel) Restart(imp *Import) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+    UPDATE imports
+    SET status = 'pending', total_rows = $1, processed = 0, matched = 0, created = 0, failed = 0,
+        finished_at = NULL, updated_at = NOW()
+    WHERE id = $2 AND (status = 'failed' OR (status IN ('pending', 'running') AND updated_at < $3))
+    RETURNING status`
+
+	args := []any{imp.TotalRows, imp.ID, time.Now().Add(-importStaleAfter)}
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&imp.Status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrEditConflict
+		}
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM import_failures WHERE import_id = $1`, imp.ID)
+	if err != nil {
+		return err
+	}
+
+	imp.Processed, imp.Matched, imp.Created, imp.Failed = 0, 0, 0, 0
+	imp.Failures = []ImportFailure{}
+	imp.FinishedAt = nil
+
+	return tx.Commit()
+}
+
+func (m ImportModel) AddFailure(importID int64, failure ImportFailure) error {
+	query := `
+    INSERT INTO import_failures (import_id, row_number, title, reason)
+    VALUES ($1, $2, $3, $4)
+    ON CONFLICT (import_id, row_number) DO UPDATE SET reason = EXCLUDED.reason`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.DB.ExecContext(ctx, query, importID, failure.Row, failure.Title, failure.Reason)
+	return err
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+)
+
+func TestImportModel_Insert(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := ImportModel{db}
+
+	imp := &Import{UserID: 1, FileHash: []byte("hash"), Source: ImportSourceGoodreads, TotalRows: 3}
+
+	err := m.Insert(imp)
+	assert.NilError(t, err)
+	assert.Equal(t, imp.Status, ImportStatusPending)
+
+	err = m.Insert(&Import{UserID: 1, FileHash: []byte("hash"), Source: ImportSourceGoodreads})
+	assert.Equal(t, err, ErrDuplicateImport)
+
+	err = m.AddFailure(imp.ID, ImportFailure{Row: 2, Title: "The Hobbit", Reason: "year: must be provided"})
+	assert.NilError(t, err)
+
+	got, err := m.GetForUserByHash(1, []byte("hash"))
+	assert.NilError(t, err)
+
+	assert.Equal(t, got.ID, imp.ID)
+	assert.Equal(t, len(got.Failures), 1)
+	assert.Equal(t, got.Failures[0].Reason, "year: must be provided")
+}
+
+func TestImportModel_Restart(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := ImportModel{db}
+
+	imp := &Import{UserID: 1, FileHash: []byte("hash"), Source: ImportSourceGoodreads, TotalRows: 3}
+	assert.NilError(t, m.Insert(imp))
+
+	assert.Equal(t, m.Restart(imp), ErrEditConflict)
+
+	imp.Status = ImportStatusFailed
+	imp.Processed = 1
+	imp.Failed = 1
+	assert.NilError(t, m.Update(imp))
+	assert.NilError(t, m.AddFailure(imp.ID, ImportFailure{Row: 2, Title: "The Hobbit", Reason: "year: must be provided"}))
+
+	assert.NilError(t, m.Restart(imp))
+
+	got, err := m.GetForUser(1, imp.ID)
+	assert.NilError(t, err)
+	assert.Equal(t, got.Status, ImportStatusPending)
+	assert.Equal(t, got.Processed, 0)
+	assert.Equal(t, len(got.Failures), 0)
+
+	imp.Status = ImportStatusCompleted
+	assert.NilError(t, m.Update(imp))
+	assert.Equal(t, m.Restart(imp), ErrEditConflict)
+}
@@ -0,0 +1,78 @@
+package models
+
+import "strings"
+
+// NormalizeISBN strips the hyphens and spaces from an ISBN and converts a
+// valid ISBN-10 to its ISBN-13 form. Anything else is returned without the
+// separators, to be rejected by ValidISBN13.
+func NormalizeISBN(s string) string {
+	s = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(s))
+
+	if len(s) != 10 || !validISBN10(s) {
+		return s
+	}
+
+	isbn := "978" + s[:9]
+
+	return isbn + string(isbn13CheckDigit(isbn))
+}
+
+// ValidISBN13 reports whether s is 13 digits with a correct check digit.
+func ValidISBN13(s string) bool {
+	if len(s) != 13 || !digits(s) {
+		return false
+	}
+
+	if !strings.HasPrefix(s, "978") && !strings.HasPrefix(s, "979") {
+		return false
+	}
+
+	return isbn13CheckDigit(s[:12]) == s[12]
+}
+
+func validISBN10(s string) bool {
+	if !digits(s[:9]) {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(s[i]-'0') * (10 - i)
+	}
+
+	switch last := s[9]; {
+	case last == 'X':
+		sum += 10
+	case last >= '0' && last <= '9':
+		sum += int(last - '0')
+	default:
+		return false
+	}
+
+	return sum%11 == 0
+}
+
+// isbn13CheckDigit computes the check digit for the first 12 digits of an
+// ISBN-13.
+func isbn13CheckDigit(s string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(s[i]-'0') * weight
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func digits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
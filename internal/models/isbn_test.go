@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+)
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		name string
+		isbn string
+		want string
+	}{
+		{name: "ISBN-10", isbn: "0261103571", want: "9780261103573"},
+		{name: "ISBN-10 with hyphens", isbn: "0-261-10357-1", want: "9780261103573"},
+		{name: "ISBN-10 with X", isbn: "080442957X", want: "9780804429573"},
+		{name: "ISBN-13 with hyphens", isbn: "978-0-261-10357-3", want: "9780261103573"},
+		{name: "Invalid ISBN-10", isbn: "0261103572", want: "0261103572"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, NormalizeISBN(tt.isbn), tt.want)
+		})
+	}
+}
+
+func TestValidISBN13(t *testing.T) {
+	tests := []struct {
+		name string
+		isbn string
+		want bool
+	}{
+		{name: "Valid", isbn: "9780261103573", want: true},
+		{name: "Valid 979", isbn: "9791032305690", want: true},
+		{name: "Wrong check digit", isbn: "9780261103574", want: false},
+		{name: "Wrong prefix", isbn: "1234567890128", want: false},
+		{name: "Letters", isbn: "978026110357X", want: false},
+		{name: "ISBN-10", isbn: "0261103571", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, ValidISBN13(tt.isbn), tt.want)
+		})
+	}
+}
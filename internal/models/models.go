@@ -3,6 +3,7 @@ package models
 import (
 	"database/sql"
 	"errors"
+	"time"
 )
 
 var (
@@ -11,15 +12,49 @@ var (
 )
 
 type Models struct {
-	Books    BookModel
-	Users    UserModel
-	UserBook UserBookModel
+	Books        BookModel
+	Users        UserModel
+	UserBook     UserBookModel
+	Permissions  PermissionsModel
+	Tokens       TokenModel
+	Reviews      ReviewModel
+	Progress     ProgressModel
+	ReadThroughs ReadThroughModel
+	Shelves      ShelfModel
+	Goals        GoalModel
+	Stats        StatsModel
+	Imports      ImportModel
+	Export       ExportModel
+	Authors      AuthorModel
+	Series       SeriesModel
+	Works        WorkModel
+	Genres       GenreModel
+	Tags         TagModel
 }
 
 func NewModels(db *sql.DB) Models {
 	return Models{
-		Books:    BookModel{DB: db},
-		Users:    UserModel{DB: db},
-		UserBook: UserBookModel{DB: db},
+		Books:        BookModel{DB: db},
+		Users:        UserModel{DB: db},
+		UserBook:     UserBookModel{DB: db},
+		Permissions:  PermissionsModel{DB: db},
+		Tokens:       TokenModel{DB: db},
+		Reviews:      ReviewModel{DB: db},
+		Progress:     ProgressModel{DB: db},
+		ReadThroughs: ReadThroughModel{DB: db},
+		Shelves:      ShelfModel{DB: db},
+		Goals:        GoalModel{DB: db},
+		Stats:        StatsModel{DB: db},
+		Imports:      ImportModel{DB: db},
+		Export:       ExportModel{DB: db},
+		Authors:      AuthorModel{DB: db},
+		Series:       SeriesModel{DB: db},
+		Works:        WorkModel{DB: db},
+		Genres:       GenreModel{DB: db},
+		Tags:         TagModel{DB: db},
 	}
 }
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -8,6 +8,17 @@ import (
 	"github.com/lib/pq"
 )
 
+const (
+	PermissionBooksRead  = "books:read"
+	PermissionBooksWrite = "books:write"
+	PermissionBooksAdmin = "books:admin"
+)
+
+// DefaultPermissions are granted to every user when their account is created.
+// Changing the catalog is granted to trusted users on purpose, see the
+// db/permissions/grant make target.
+var DefaultPermissions = []string{PermissionBooksRead}
+
 type Permissions []string
 
 func (p Permissions) Include(code string) bool {
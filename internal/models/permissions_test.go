@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+)
+
+func TestPermissionsModel_GetAllForUser(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID int64
+		code   string
+		want   bool
+	}{
+		{name: "Granted permission", userID: 1, code: PermissionBooksRead, want: true},
+		{name: "Missing permission", userID: 1, code: PermissionBooksWrite, want: false},
+		{name: "Non-existent user", userID: 2, code: PermissionBooksRead, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := NewTestDB(t)
+
+			m := PermissionsModel{db}
+
+			permissions, err := m.GetAllForUser(tt.userID)
+
+			assert.NilError(t, err)
+			assert.Equal(t, permissions.Include(tt.code), tt.want)
+		})
+	}
+}
+
+func TestPermissionsModel_AddForUser(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := PermissionsModel{db}
+
+	err := m.AddForUser(1, PermissionBooksWrite)
+	assert.NilError(t, err)
+
+	permissions, err := m.GetAllForUser(1)
+	assert.NilError(t, err)
+	assert.Equal(t, permissions.Include(PermissionBooksWrite), true)
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+// Progress is a single reading session log entry of a shelved book.
+type Progress struct {
+	ID         int64     `json:"id"`
+	UserBookID int64     `json:"-"`
+	Page       int32     `json:"page"`
+	Percentage float32   `json:"percentage"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func ValidateProgress(v *validator.Validator, progress *Progress, pages int32) {
+	v.Check(progress.Page >= 0, "page", "must not be negative")
+	v.Check(progress.Page <= pages, "page", "must not be greater than the number of pages of the book")
+
+	v.Check(progress.Percentage >= 0, "percentage", "must not be negative")
+	v.Check(progress.Percentage <= 100, "percentage", "must not be greater than 100")
+}
+
+// NewProgress builds a progress entry from either a page or a percentage and
+// fills in the other value based on the number of pages of the book.
+func NewProgress(userBookID int64, page *int32, percentage *float32, pages int32) *Progress {
+	progress := &Progress{UserBookID: userBookID}
+
+	switch {
+	case page != nil:
+		progress.Page = *page
+		if pages > 0 {
+			progress.Percentage = float32(*page) / float32(pages) * 100
+		}
+	case percentage != nil:
+		progress.Percentage = *percentage
+		progress.Page = int32(float32(pages) * *percentage / 100)
+	}
+
+	return progress
+}
+
+// Apply updates the reading status of the shelved book for the given
+// progress. Reaching the last page marks the book as read.
+func (progress *Progress) Apply(userBook *UserBook, pages int32) {
+	userBook.Page = progress.Page
+
+	switch {
+	case progress.Page >= pages:
+		userBook.SetStatus(StatusRead)
+	case userBook.Status != StatusReading:
+		userBook.SetStatus(StatusReading)
+	}
+}
+
+type ProgressModel struct {
+	DB *sql.DB
+}
+
+// Insert stores the progress entry and the updated shelf entry in a single
+// transaction. ErrEditConflict is returned if the shelf entry changed since it
+// was read.
+func (m ProgressModel) Insert(progress *Progress, userBook *UserBook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+    INSERT INTO reading_progress (userbook_id, page, percentage)
+    VALUES ($1, $2, $3)
+    RETURNING id, created_at`
+
+	err = tx.QueryRowContext(ctx, query, progress.UserBookID, progress.Page, progress.Percentage).
+		Scan(&progress.ID, &progress.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	query = `
+    UPDATE usersBooksRelation
+    SET read = $1, status = $2, current_page = $3, read_at = $4, version = version + 1
+    WHERE id = $5 AND version = $6
+    RETURNING version`
+
+	args := []any{
+		userBook.Read,
+		userBook.Status,
+		userBook.Page,
+		userBook.ReadAt,
+		userBook.ID,
+		userBook.Version,
+	}
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&userBook.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrEditConflict
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m ProgressModel) ListForUserBook(userBookID int64) ([]*Progress, error) {
+	query := `
+    SELECT id, userbook_id, page, percentage, created_at
+    FROM reading_progress
+    WHERE userbook_id = $1
+    ORDER BY created_at DESC, id DESC`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userBookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*Progress{}
+
+	for rows.Next() {
+		var progress Progress
+
+		err := rows.Scan(
+			&progress.ID,
+			&progress.UserBookID,
+			&progress.Page,
+			&progress.Percentage,
+			&progress.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, &progress)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
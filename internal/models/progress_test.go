@@ -0,0 +1,119 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+func TestNewProgress(t *testing.T) {
+	page := int32(160)
+	percentage := float32(25)
+
+	tests := []struct {
+		name           string
+		page           *int32
+		percentage     *float32
+		wantPage       int32
+		wantPercentage float32
+	}{
+		{name: "From page", page: &page, wantPage: 160, wantPercentage: 50},
+		{name: "From percentage", percentage: &percentage, wantPage: 80, wantPercentage: 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := NewProgress(1, tt.page, tt.percentage, 320)
+
+			assert.Equal(t, progress.Page, tt.wantPage)
+			assert.Equal(t, progress.Percentage, tt.wantPercentage)
+		})
+	}
+}
+
+func TestProgressApply(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		page       int32
+		wantStatus string
+		wantRead   bool
+	}{
+		{name: "Start reading", status: StatusWantToRead, page: 12, wantStatus: StatusReading},
+		{name: "Resume abandoned", status: StatusAbandoned, page: 140, wantStatus: StatusReading},
+		{name: "Last page", status: StatusReading, page: 320, wantStatus: StatusRead, wantRead: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userBook := &UserBook{Status: tt.status}
+
+			progress := &Progress{Page: tt.page}
+			progress.Apply(userBook, 320)
+
+			assert.Equal(t, userBook.Status, tt.wantStatus)
+			assert.Equal(t, userBook.Read, tt.wantRead)
+			assert.Equal(t, userBook.ReadAt.IsZero(), !tt.wantRead)
+			assert.Equal(t, userBook.Page, tt.page)
+		})
+	}
+}
+
+func TestValidateProgress(t *testing.T) {
+	tests := []struct {
+		name      string
+		progress  Progress
+		wantError map[string]string
+	}{
+		{
+			name:      "Valid progress",
+			progress:  Progress{Page: 143, Percentage: 44.6},
+			wantError: nil,
+		},
+		{
+			name:      "Past last page",
+			progress:  Progress{Page: 400, Percentage: 100},
+			wantError: map[string]string{"page": "must not be greater than the number of pages of the book"},
+		},
+		{
+			name:      "Percentage above 100",
+			progress:  Progress{Page: 320, Percentage: 120},
+			wantError: map[string]string{"percentage": "must not be greater than 100"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validator.New()
+
+			ValidateProgress(v, &tt.progress, 320)
+			assert.DeepEqual(t, tt.wantError, v.Errors)
+		})
+	}
+}
+
+func TestProgressModel_InsertForDeletedBook(t *testing.T) {
+	db := NewTestDB(t)
+
+	books := BookModel{db}
+
+	err := books.Delete(2)
+	assert.NilError(t, err)
+
+	book, err := books.GetIncludingDeleted(2)
+	assert.NilError(t, err)
+	assert.Equal(t, book.Pages, int32(700))
+
+	userBook, err := UserBookModel{db}.GetForUser(1, 2)
+	assert.NilError(t, err)
+
+	page := int32(350)
+
+	progress := NewProgress(userBook.ID, &page, nil, book.Pages)
+	progress.Apply(userBook, book.Pages)
+
+	err = ProgressModel{db}.Insert(progress, userBook)
+	assert.NilError(t, err)
+	assert.Equal(t, progress.Percentage, float32(50))
+}
@@ -0,0 +1,211 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+// ReadThrough is a single pass through a shelved book. Re-reads add further
+// read-throughs to the same usersBooksRelation entry.
+type ReadThrough struct {
+	ID         int64      `json:"id"`
+	UserBookID int64      `json:"-"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Rating     float32    `json:"rating"`
+	Notes      string     `json:"notes"`
+	CreatedAt  time.Time  `json:"-"`
+	Version    int32      `json:"-"`
+}
+
+func ValidateReadThrough(v *validator.Validator, readThrough *ReadThrough) {
+	v.Check(readThrough.Rating >= 0, "rating", "must not be negative")
+	v.Check(readThrough.Rating <= 5, "rating", "must not be greater than 5")
+
+	v.Check(len(readThrough.Notes) <= 5000, "notes", "must be less than 5000 characters")
+
+	if readThrough.StartedAt != nil {
+		v.Check(readThrough.StartedAt.Year() >= 1900, "started_at", "must be greater than 1900")
+		v.Check(readThrough.StartedAt.Compare(time.Now()) <= 0, "started_at", "must not be in the future")
+	}
+
+	if readThrough.FinishedAt != nil {
+		v.Check(readThrough.FinishedAt.Year() >= 1900, "finished_at", "must be greater than 1900")
+		v.Check(readThrough.FinishedAt.Compare(time.Now()) <= 0, "finished_at", "must not be in the future")
+	}
+
+	if readThrough.StartedAt != nil && readThrough.FinishedAt != nil {
+		v.Check(
+			!readThrough.FinishedAt.Before(*readThrough.StartedAt),
+			"finished_at",
+			"must not be before started_at",
+		)
+	}
+}
+
+type ReadThroughModel struct {
+	DB *sql.DB
+}
+
+func (m ReadThroughModel) Insert(readThrough *ReadThrough) error {
+	query := `
+    INSERT INTO read_throughs (userbook_id, started_at, finished_at, rating, notes)
+    VALUES ($1, $2, $3, $4, $5)
+    RETURNING id, created_at, version`
+
+	args := []any{
+		readThrough.UserBookID,
+		readThrough.StartedAt,
+		readThrough.FinishedAt,
+		readThrough.Rating,
+		readThrough.Notes,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.DB.QueryRowContext(ctx, query, args...).
+		Scan(&readThrough.ID, &readThrough.CreatedAt, &readThrough.Version)
+}
+
+func (m ReadThroughModel) GetForUserBook(userBookID, id int64) (*ReadThrough, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+    SELECT id, userbook_id, started_at, finished_at, rating, notes, created_at, version
+    FROM read_throughs
+    WHERE id = $1 AND userbook_id = $2`
+
+	var readThrough ReadThrough
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, id, userBookID).Scan(
+		&readThrough.ID,
+		&readThrough.UserBookID,
+		&readThrough.StartedAt,
+		&readThrough.FinishedAt,
+		&readThrough.Rating,
+		&readThrough.Notes,
+		&readThrough.CreatedAt,
+		&readThrough.Version,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return &readThrough, nil
+}
+
+func (m ReadThroughModel) ListForUserBook(userBookID int64) ([]*ReadThrough, error) {
+	query := `
+    SELECT id, userbook_id, started_at, finished_at, rating, notes, created_at, version
+    FROM read_throughs
+    WHERE userbook_id = $1
+    ORDER BY COALESCE(started_at, finished_at, created_at) ASC, id ASC`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userBookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	readThroughs := []*ReadThrough{}
+
+	for rows.Next() {
+		var readThrough ReadThrough
+
+		err := rows.Scan(
+			&readThrough.ID,
+			&readThrough.UserBookID,
+			&readThrough.StartedAt,
+			&readThrough.FinishedAt,
+			&readThrough.Rating,
+			&readThrough.Notes,
+			&readThrough.CreatedAt,
+			&readThrough.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		readThroughs = append(readThroughs, &readThrough)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return readThroughs, nil
+}
+
+func (m ReadThroughModel) Update(readThrough *ReadThrough) error {
+	query := `
+    UPDATE read_throughs
+    SET started_at = $1, finished_at = $2, rating = $3, notes = $4, version = version + 1
+    WHERE id = $5 AND version = $6
+    RETURNING version`
+
+	args := []any{
+		readThrough.StartedAt,
+		readThrough.FinishedAt,
+		readThrough.Rating,
+		readThrough.Notes,
+		readThrough.ID,
+		readThrough.Version,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&readThrough.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrEditConflict
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (m ReadThroughModel) DeleteForUserBook(userBookID, id int64) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	query := `
+    DELETE FROM read_throughs
+    WHERE id = $1 AND userbook_id = $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, id, userBookID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+func TestReadThroughModel_ListForUserBook(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := ReadThroughModel{db}
+
+	readThroughs, err := m.ListForUserBook(14)
+	assert.NilError(t, err)
+	assert.Equal(t, len(readThroughs), 1)
+
+	startedAt := time.Date(2025, 01, 02, 0, 0, 0, 0, time.UTC)
+
+	err = m.Insert(&ReadThrough{UserBookID: 14, StartedAt: &startedAt})
+	assert.NilError(t, err)
+
+	readThroughs, err = m.ListForUserBook(14)
+	assert.NilError(t, err)
+	assert.Equal(t, len(readThroughs), 2)
+}
+
+func TestReadThroughModel_Reread(t *testing.T) {
+	db := NewTestDB(t)
+
+	userBooks := UserBookModel{db}
+	m := ReadThroughModel{db}
+
+	userBook, err := userBooks.Get(14)
+	assert.NilError(t, err)
+
+	userBook.SetStatus(StatusReading)
+	assert.NilError(t, userBooks.Update(userBook))
+
+	userBook.SetStatus(StatusRead)
+	assert.NilError(t, userBooks.Update(userBook))
+
+	readThroughs, err := m.ListForUserBook(14)
+	assert.NilError(t, err)
+	assert.Equal(t, len(readThroughs), 2)
+
+	first, second := readThroughs[0], readThroughs[1]
+	assert.Equal(t, first.Rating, float32(4.5))
+	assert.Equal(t, first.FinishedAt.Year(), 2024)
+
+	assert.Equal(t, second.StartedAt != nil, true)
+	assert.Equal(t, second.FinishedAt != nil, true)
+	assert.Equal(t, second.FinishedAt.Before(*second.StartedAt), false)
+	assert.Equal(t, second.FinishedAt.Year(), time.Now().Year())
+	assert.Equal(t, second.Rating, float32(0))
+}
+
+func TestValidateReadThrough(t *testing.T) {
+	startedAt := time.Date(2024, 03, 28, 20, 0, 0, 0, time.UTC)
+	finishedAt := time.Date(2024, 04, 10, 14, 30, 0, 0, time.UTC)
+	future := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name        string
+		readThrough ReadThrough
+		wantError   map[string]string
+	}{
+		{
+			name:        "Valid read-through",
+			readThrough: ReadThrough{StartedAt: &startedAt, FinishedAt: &finishedAt, Rating: 4},
+			wantError:   nil,
+		},
+		{
+			name:        "Finished before started",
+			readThrough: ReadThrough{StartedAt: &finishedAt, FinishedAt: &startedAt},
+			wantError:   map[string]string{"finished_at": "must not be before started_at"},
+		},
+		{
+			name:        "Finished in future",
+			readThrough: ReadThrough{FinishedAt: &future},
+			wantError:   map[string]string{"finished_at": "must not be in the future"},
+		},
+		{
+			name:        "Rating above 5",
+			readThrough: ReadThrough{Rating: 6},
+			wantError:   map[string]string{"rating": "must not be greater than 5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validator.New()
+
+			ValidateReadThrough(v, &tt.readThrough)
+			assert.DeepEqual(t, tt.wantError, v.Errors)
+		})
+	}
+}
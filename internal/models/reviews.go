@@ -0,0 +1,231 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrOwnReview            = errors.New("own review")
+	ErrDuplicateHelpfulVote = errors.New("duplicate helpful vote")
+)
+
+// Review is the public view of a usersBooksRelation row that has a review body.
+type Review struct {
+	ID           int64     `json:"id"`
+	BookID       int64     `json:"book_id"`
+	Reviewer     Reviewer  `json:"reviewer"`
+	Rating       float32   `json:"rating"`
+	Body         string    `json:"body"`
+	ReviewedAt   time.Time `json:"reviewed_at"`
+	HelpfulCount int       `json:"helpful_count"`
+}
+
+type Reviewer struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Avatar string `json:"avatar"`
+}
+
+// RatingStats are the rating aggregates of a work. Histogram holds the number
+// of ratings per star, from 1 star at index 0 to 5 stars at index 4.
+type RatingStats struct {
+	AverageRating float64 `json:"average_rating"`
+	RatingsCount  int     `json:"ratings_count"`
+	Histogram     [5]int  `json:"histogram"`
+}
+
+type ReviewModel struct {
+	DB *sql.DB
+}
+
+func (m ReviewModel) Get(id int64) (*Review, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+    SELECT ub.id, ub.bookId, u.id, u.name, u.avatar, ub.rating, ub.reviewBody, ub.reviewed_at, ub.helpful_count
+    FROM usersBooksRelation ub
+    INNER JOIN users u ON u.id = ub.userId
+    WHERE ub.id = $1 AND ub.reviewBody <> ''`
+
+	var review Review
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, id).Scan(
+		&review.ID,
+		&review.BookID,
+		&review.Reviewer.ID,
+		&review.Reviewer.Name,
+		&review.Reviewer.Avatar,
+		&review.Rating,
+		&review.Body,
+		&review.ReviewedAt,
+		&review.HelpfulCount,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+// ListForWork lists the reviews of all editions of a work. BookID tells which
+// edition a review was written for.
+func (m ReviewModel) ListForWork(workID int64, filters Filters) ([]*Review, Metadata, error) {
+	query := fmt.Sprintf(`
+    SELECT count(*) OVER(), ub.id, ub.bookId, u.id, u.name, u.avatar, ub.rating, ub.reviewBody, ub.reviewed_at, ub.helpful_count
+    FROM usersBooksRelation ub
+    INNER JOIN users u ON u.id = ub.userId
+    INNER JOIN books b ON b.id = ub.bookId
+    WHERE b.work_id = $1 AND ub.reviewBody <> ''
+    ORDER BY ub.%s %s, ub.id ASC
+    LIMIT $2 OFFSET $3`, filters.sortColumn(), filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, workID, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	reviews := []*Review{}
+
+	for rows.Next() {
+		var review Review
+
+		err := rows.Scan(
+			&totalRecords,
+			&review.ID,
+			&review.BookID,
+			&review.Reviewer.ID,
+			&review.Reviewer.Name,
+			&review.Reviewer.Avatar,
+			&review.Rating,
+			&review.Body,
+			&review.ReviewedAt,
+			&review.HelpfulCount,
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		reviews = append(reviews, &review)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+
+	return reviews, metadata, nil
+}
+
+// RatingStatsForWork sums the aggregates of all editions of a work. They are
+// maintained by the usersbooksrelation_rating_stats trigger, so no reviews are
+// scanned.
+func (m ReviewModel) RatingStatsForWork(workID int64) (RatingStats, error) {
+	query := `
+    SELECT COALESCE(sum(s.ratings_count), 0), COALESCE(sum(s.ratings_sum), 0), ARRAY[
+        COALESCE(sum(s.histogram[1]), 0),
+        COALESCE(sum(s.histogram[2]), 0),
+        COALESCE(sum(s.histogram[3]), 0),
+        COALESCE(sum(s.histogram[4]), 0),
+        COALESCE(sum(s.histogram[5]), 0)
+    ]
+    FROM book_rating_stats s
+    INNER JOIN books b ON b.id = s.book_id
+    WHERE b.work_id = $1`
+
+	var (
+		stats     RatingStats
+		sum       float64
+		histogram []int64
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, workID).Scan(&stats.RatingsCount, &sum, pq.Array(&histogram))
+	if err != nil {
+		return stats, err
+	}
+
+	if stats.RatingsCount > 0 {
+		stats.AverageRating = sum / float64(stats.RatingsCount)
+	}
+
+	for i := 0; i < len(histogram) && i < len(stats.Histogram); i++ {
+		stats.Histogram[i] = int(histogram[i])
+	}
+
+	return stats, nil
+}
+
+func (m ReviewModel) AddHelpfulVote(reviewID, userID int64) error {
+	query := `
+    INSERT INTO review_helpful_votes (review_id, user_id)
+    SELECT id, $2 FROM usersBooksRelation
+    WHERE id = $1 AND reviewBody <> '' AND userId <> $2
+    RETURNING review_id`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var id int64
+
+	err := m.DB.QueryRowContext(ctx, query, reviewID, userID).Scan(&id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrOwnReview
+		case strings.Contains(err.Error(), "review_helpful_votes_pkey"):
+			return ErrDuplicateHelpfulVote
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m ReviewModel) DeleteHelpfulVote(reviewID, userID int64) error {
+	query := `
+    DELETE FROM review_helpful_votes
+    WHERE review_id = $1 AND user_id = $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, reviewID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
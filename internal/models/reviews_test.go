@@ -0,0 +1,56 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+)
+
+func TestReviewModel_Get(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int64
+		wantErr error
+	}{
+		{name: "Valid review", id: 14, wantErr: nil},
+		{name: "Non-existent review", id: 10, wantErr: ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := NewTestDB(t)
+
+			m := ReviewModel{db}
+
+			_, err := m.Get(tt.id)
+
+			assert.Equal(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestReviewModel_RatingStatsForWork(t *testing.T) {
+	tests := []struct {
+		name      string
+		workID    int64
+		wantCount int
+		wantStars [5]int
+	}{
+		{name: "Rated work", workID: 2, wantCount: 1, wantStars: [5]int{0, 0, 0, 0, 1}},
+		{name: "Unrated work", workID: 1, wantCount: 0, wantStars: [5]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := NewTestDB(t)
+
+			m := ReviewModel{db}
+
+			stats, err := m.RatingStatsForWork(tt.workID)
+
+			assert.NilError(t, err)
+			assert.Equal(t, stats.RatingsCount, tt.wantCount)
+			assert.Equal(t, stats.Histogram, tt.wantStars)
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+type Series struct {
+	ID          int64     `json:"id"          example:"3"`
+	Name        string    `json:"name"        example:"The Lord of the Rings"`
+	Description string    `json:"description" example:"High fantasy in three volumes."`
+	Volumes     []*Volume `json:"volumes,omitempty"`
+	CreatedAt   time.Time `json:"-"`
+	Version     int32     `json:"-"`
+}
+
+// Volume is a book at its position within a series. Positions may be
+// fractional, like 2.5 for a novella published between the second and third
+// book. Status is the reading status of the requesting user and is empty when
+// the book isn't on their shelf.
+type Volume struct {
+	Position float64 `json:"position" example:"2"`
+	BookID   int64   `json:"book_id"  example:"7"`
+	Book     *Book   `json:"book"`
+	Status   string  `json:"status,omitempty" example:"read"`
+}
+
+// NextVolume is the first volume of a series the user has started but not yet
+// read.
+type NextVolume struct {
+	SeriesID   int64  `json:"series_id"   example:"3"`
+	SeriesName string `json:"series_name" example:"The Lord of the Rings"`
+	Volume
+}
+
+func ValidateSeries(v *validator.Validator, series *Series) {
+	v.Check(strings.TrimSpace(series.Name) != "", "name", "must be provided")
+	v.Check(len(series.Name) <= 300, "name", "must not be more than 300 bytes long")
+
+	v.Check(len(series.Description) <= 5000, "description", "must not be more than 5000 bytes long")
+}
+
+func ValidateVolumePosition(v *validator.Validator, position float64) {
+	v.Check(position >= 0, "position", "must not be negative")
+	v.Check(position < 10_000, "position", "must be less than 10000")
+	v.Check(math.Abs(math.Round(position*100)-position*100) < 1e-6, "position", "must not have more than two decimal places")
+}
+
+type SeriesModel struct {
+	DB *sql.DB
+}
+
+func (m SeriesModel) Insert(series *Series) error {
+	query := `
+    INSERT INTO series (name, description)
+    VALUES ($1, $2)
+    RETURNING id, created_at, version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.DB.QueryRowContext(ctx, query, series.Name, series.Description).
+		Scan(&series.ID, &series.CreatedAt, &series.Version)
+}
+
+func (m SeriesModel) Get(id int64) (*Series, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+    SELECT id, name, description, created_at, version
+    FROM series
+    WHERE id = $1`
+
+	var series Series
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, id).
+		Scan(&series.ID, &series.Name, &series.Description, &series.CreatedAt, &series.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+func (m SeriesModel) List(name string, filters Filters) ([]*Series, Metadata, error) {
+	query := fmt.Sprintf(`
+    SELECT count(*) OVER(), id, name, description, created_at, version
+    FROM series
+    WHERE (name ILIKE $4 OR $1 = '')
+    ORDER BY %s %s, id ASC
+    LIMIT $2 OFFSET $3`, filters.sortColumn(), filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, name, filters.limit(), filters.offset(), containsPattern(name))
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	list := []*Series{}
+
+	for rows.Next() {
+		var series Series
+
+		err := rows.Scan(&totalRecords, &series.ID, &series.Name, &series.Description, &series.CreatedAt, &series.Version)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		list = append(list, &series)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+
+	return list, metadata, nil
+}
+
+func (m SeriesModel) Update(series *Series) error {
+	query := `
+    UPDATE series
+    SET name = $1, description = $2, version = version + 1
+    WHERE id = $3 AND version = $4
+    RETURNING version`
+
+	args := []any{series.Name, series.Description, series.ID, series.Version}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&series.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrEditConflict
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (m SeriesModel) Delete(id int64) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	query := `
+    DELETE FROM series
+    WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// AddBook puts the book into the series at position, or moves it there if it
+// is already part of the series.
+func (m SeriesModel) AddBook(seriesID, bookID int64, position float64) error {
+	query := `
+    INSERT INTO series_books (series_id, book_id, position)
+    VALUES ($1, $2, $3)
+    ON CONFLICT (series_id, book_id) DO UPDATE SET position = EXCLUDED.position`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.DB.ExecContext(ctx, query, seriesID, bookID, position)
+	return err
+}
+
+func (m SeriesModel) RemoveBook(seriesID, bookID int64) error {
+	query := `
+    DELETE FROM series_books
+    WHERE series_id = $1 AND book_id = $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, seriesID, bookID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Volumes lists the books of the series in reading order, with the status
+// each one has on the shelf of the user.
+func (m SeriesModel) Volumes(seriesID, userID int64) ([]*Volume, error) {
+	query := fmt.Sprintf(`
+    SELECT sb.position, COALESCE(ub.status, ''), %s
+    FROM series_books sb
+    INNER JOIN books ON books.id = sb.book_id
+    LEFT JOIN usersBooksRelation ub ON ub.bookId = sb.book_id AND ub.userId = $2
+    WHERE sb.series_id = $1 AND books.deleted_at IS NULL
+    ORDER BY sb.position ASC, books.year ASC, books.id ASC`, qualifiedBookColumns)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, seriesID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	volumes := []*Volume{}
+
+	for rows.Next() {
+		volume := Volume{Book: &Book{}}
+
+		err := rows.Scan(append([]any{&volume.Position, &volume.Status}, scanBookDest(volume.Book)...)...)
+		if err != nil {
+			return nil, err
+		}
+
+		volume.BookID = volume.Book.ID
+
+		volumes = append(volumes, &volume)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return volumes, nil
+}
+
+// NextUnreadForUser returns, for every series the user has read at least one
+// volume of, the first volume that is neither read nor abandoned. Finished
+// series are left out.
+func (m SeriesModel) NextUnreadForUser(userID int64) ([]*NextVolume, error) {
+	query := fmt.Sprintf(`
+    SELECT DISTINCT ON (s.name, s.id) s.id, s.name, sb.position, COALESCE(ub.status, ''), %s
+    FROM series s
+    INNER JOIN series_books sb ON sb.series_id = s.id
+    INNER JOIN books ON books.id = sb.book_id
+    LEFT JOIN usersBooksRelation ub ON ub.bookId = sb.book_id AND ub.userId = $1
+    WHERE EXISTS (
+        SELECT 1 FROM series_books started
+        INNER JOIN usersBooksRelation rb ON rb.bookId = started.book_id
+        WHERE started.series_id = s.id AND rb.userId = $1 AND rb.status = 'read'
+    )
+    AND (ub.status IS NULL OR ub.status NOT IN ('read', 'abandoned'))
+    AND books.deleted_at IS NULL
+    ORDER BY s.name ASC, s.id ASC, sb.position ASC, books.year ASC, books.id ASC`, qualifiedBookColumns)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	next := []*NextVolume{}
+
+	for rows.Next() {
+		volume := NextVolume{Volume: Volume{Book: &Book{}}}
+
+		dest := []any{&volume.SeriesID, &volume.SeriesName, &volume.Position, &volume.Status}
+
+		err := rows.Scan(append(dest, scanBookDest(volume.Book)...)...)
+		if err != nil {
+			return nil, err
+		}
+
+		volume.BookID = volume.Book.ID
+
+		next = append(next, &volume)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return next, nil
+}
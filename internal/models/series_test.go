@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+func TestSeriesModel_Volumes(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := SeriesModel{db}
+
+	volumes, err := m.Volumes(1, 1)
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(volumes), 2)
+	assert.Equal(t, volumes[0].BookID, int64(2))
+	assert.Equal(t, volumes[0].Status, StatusRead)
+	assert.Equal(t, volumes[1].Position, 1.5)
+	assert.Equal(t, volumes[1].Status, "")
+}
+
+func TestSeriesModel_NextUnreadForUser(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := SeriesModel{db}
+
+	next, err := m.NextUnreadForUser(1)
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(next), 1)
+	assert.Equal(t, next[0].SeriesName, "Test Saga")
+	assert.Equal(t, next[0].BookID, int64(1))
+}
+
+func TestValidateVolumePosition(t *testing.T) {
+	tests := []struct {
+		name      string
+		position  float64
+		wantError map[string]string
+	}{
+		{name: "Whole number", position: 2, wantError: nil},
+		{name: "Novella", position: 2.5, wantError: nil},
+		{name: "Two decimals", position: 0.29, wantError: nil},
+		{name: "Negative", position: -1, wantError: map[string]string{"position": "must not be negative"}},
+		{name: "Too precise", position: 2.125, wantError: map[string]string{"position": "must not have more than two decimal places"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validator.New()
+
+			ValidateVolumePosition(v, tt.position)
+			assert.DeepEqual(t, tt.wantError, v.Errors)
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+var ErrDuplicateShelfName = errors.New("duplicate shelf name")
+
+// Shelf is a named, user-defined collection of shelved books. Books are linked
+// through shelves_books, so removing a shelf leaves usersBooksRelation intact.
+type Shelf struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"-"`
+	Name        string    `json:"name"        example:"Book club 2026"`
+	Description string    `json:"description" example:"Everything we read this year"`
+	BookCount   int       `json:"book_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	Version     int32     `json:"-"`
+}
+
+func ValidateShelf(v *validator.Validator, shelf *Shelf) {
+	v.Check(strings.TrimSpace(shelf.Name) != "", "name", "must be provided")
+	v.Check(len(shelf.Name) <= 100, "name", "must not be more than 100 bytes long")
+
+	v.Check(len(shelf.Description) <= 1000, "description", "must not be more than 1000 bytes long")
+}
+
+type ShelfModel struct {
+	DB *sql.DB
+}
+
+func (m ShelfModel) Insert(shelf *Shelf) error {
+	query := `
+    INSERT INTO shelves (user_id, name, description)
+    VALUES ($1, $2, $3)
+    RETURNING id, created_at, version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, shelf.UserID, shelf.Name, shelf.Description).
+		Scan(&shelf.ID, &shelf.CreatedAt, &shelf.Version)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "shelves_user_id_name_key"):
+			return ErrDuplicateShelfName
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m ShelfModel) GetForUser(userID, id int64) (*Shelf, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+    SELECT s.id, s.user_id, s.name, s.description, count(sb.userbook_id), s.created_at, s.version
+    FROM shelves s
+    LEFT JOIN shelves_books sb ON sb.shelf_id = s.id
+    WHERE s.id = $1 AND s.user_id = $2
+    GROUP BY s.id`
+
+	var shelf Shelf
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, id, userID).Scan(
+		&shelf.ID,
+		&shelf.UserID,
+		&shelf.Name,
+		&shelf.Description,
+		&shelf.BookCount,
+		&shelf.CreatedAt,
+		&shelf.Version,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return &shelf, nil
+}
+
+func (m ShelfModel) GetForUserByName(userID int64, name string) (*Shelf, error) {
+	query := `
+    SELECT s.id, s.user_id, s.name, s.description, count(sb.userbook_id), s.created_at, s.version
+    FROM shelves s
+    LEFT JOIN shelves_books sb ON sb.shelf_id = s.id
+    WHERE s.user_id = $1 AND s.name = $2
+    GROUP BY s.id`
+
+	var shelf Shelf
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, userID, name).Scan(
+		&shelf.ID,
+		&shelf.UserID,
+		&shelf.Name,
+		&shelf.Description,
+		&shelf.BookCount,
+		&shelf.CreatedAt,
+		&shelf.Version,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return &shelf, nil
+}
+
+func (m ShelfModel) ListForUser(userID int64) ([]*Shelf, error) {
+	query := `
+    SELECT s.id, s.user_id, s.name, s.description, count(sb.userbook_id), s.created_at, s.version
+    FROM shelves s
+    LEFT JOIN shelves_books sb ON sb.shelf_id = s.id
+    WHERE s.user_id = $1
+    GROUP BY s.id
+    ORDER BY s.name ASC, s.id ASC`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shelves := []*Shelf{}
+
+	for rows.Next() {
+		var shelf Shelf
+
+		err := rows.Scan(
+			&shelf.ID,
+			&shelf.UserID,
+			&shelf.Name,
+			&shelf.Description,
+			&shelf.BookCount,
+			&shelf.CreatedAt,
+			&shelf.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		shelves = append(shelves, &shelf)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return shelves, nil
+}
+
+func (m ShelfModel) Update(shelf *Shelf) error {
+	query := `
+    UPDATE shelves
+    SET name = $1, description = $2, version = version + 1
+    WHERE id = $3 AND version = $4
+    RETURNING version`
+
+	args := []any{shelf.Name, shelf.Description, shelf.ID, shelf.Version}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&shelf.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEditConflict
+		case strings.Contains(err.Error(), "shelves_user_id_name_key"):
+			return ErrDuplicateShelfName
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteForUser removes the shelf and its shelves_books links. The shelved
+// books themselves stay in usersBooksRelation.
+func (m ShelfModel) DeleteForUser(userID, id int64) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	query := `
+    DELETE FROM shelves
+    WHERE id = $1 AND user_id = $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// AddBook puts the shelved book on the shelf, or moves it if it is already
+// there. A nil position appends the book after the last one on the shelf.
+func (m ShelfModel) AddBook(shelfID, userBookID int64, position *int32) (int32, error) {
+	query := `
+    INSERT INTO shelves_books (shelf_id, userbook_id, position)
+    VALUES ($1, $2, COALESCE($3, (SELECT COALESCE(max(position) + 1, 0) FROM shelves_books WHERE shelf_id = $1)))
+    ON CONFLICT (shelf_id, userbook_id) DO UPDATE SET position = COALESCE($3, shelves_books.position)
+    RETURNING position`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var newPosition int32
+
+	err := m.DB.QueryRowContext(ctx, query, shelfID, userBookID, position).Scan(&newPosition)
+	return newPosition, err
+}
+
+func (m ShelfModel) RemoveBook(shelfID, userBookID int64) error {
+	query := `
+    DELETE FROM shelves_books
+    WHERE shelf_id = $1 AND userbook_id = $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, shelfID, userBookID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+func TestShelfModel_Insert(t *testing.T) {
+	tests := []struct {
+		name    string
+		shelf   Shelf
+		wantErr error
+	}{
+		{name: "Valid shelf", shelf: Shelf{UserID: 1, Name: "Book club 2026"}, wantErr: nil},
+		{name: "Duplicate name", shelf: Shelf{UserID: 1, Name: "Favorites"}, wantErr: ErrDuplicateShelfName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := NewTestDB(t)
+
+			m := ShelfModel{db}
+
+			err := m.Insert(&tt.shelf)
+
+			assert.Equal(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestShelfModel_DeleteKeepsUserBooks(t *testing.T) {
+	db := NewTestDB(t)
+
+	shelves := ShelfModel{db}
+	userBooks := UserBookModel{db}
+
+	err := shelves.DeleteForUser(1, 3)
+	assert.NilError(t, err)
+
+	_, err = userBooks.GetForUser(1, 2)
+	assert.NilError(t, err)
+}
+
+func TestValidateShelf(t *testing.T) {
+	tests := []struct {
+		name      string
+		shelf     Shelf
+		wantError map[string]string
+	}{
+		{name: "Valid shelf", shelf: Shelf{Name: "Favorites"}, wantError: nil},
+		{name: "Missing name", shelf: Shelf{Name: "  "}, wantError: map[string]string{"name": "must be provided"}},
+		{
+			name:      "Name too long",
+			shelf:     Shelf{Name: strings.Repeat("a", 101)},
+			wantError: map[string]string{"name": "must not be more than 100 bytes long"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validator.New()
+
+			ValidateShelf(v, &tt.shelf)
+			assert.DeepEqual(t, tt.wantError, v.Errors)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+// Stats summarises the reading of a single user, either for one calendar year
+// or, when Year is zero, for their whole history. A book counts as read once
+// for every finished read-through, so re-reads show up in every month they
+// were finished in.
+type Stats struct {
+	Year              int           `json:"year,omitempty"  example:"2026"`
+	BooksRead         int           `json:"books_read"      example:"23"`
+	PagesRead         int           `json:"pages_read"      example:"8120"`
+	AverageRating     float64       `json:"average_rating"  example:"3.8"`
+	AverageDaysToRead *float64      `json:"average_days_to_read"`
+	Months            []MonthStats  `json:"months"`
+	Genres            []GenreStats  `json:"genres"`
+	FavoriteAuthors   []AuthorStats `json:"favorite_authors"`
+	LongestBook       *StatsBook    `json:"longest_book"`
+	ShortestBook      *StatsBook    `json:"shortest_book"`
+}
+
+type MonthStats struct {
+	Month     string `json:"month" example:"2026-03"`
+	BooksRead int    `json:"books_read"`
+	PagesRead int    `json:"pages_read"`
+}
+
+type GenreStats struct {
+	Genre     string `json:"genre" example:"Fantasy"`
+	BooksRead int    `json:"books_read"`
+}
+
+type AuthorStats struct {
+	Author        string  `json:"author" example:"Ursula K. Le Guin"`
+	BooksRead     int     `json:"books_read"`
+	AverageRating float64 `json:"average_rating"`
+}
+
+type StatsBook struct {
+	ID     int64  `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	Pages  int32  `json:"pages"`
+}
+
+func ValidateStatsYear(v *validator.Validator, year int) {
+	v.Check(year == 0 || year >= 1900, "year", "must be greater than 1900")
+	v.Check(year <= time.Now().Year(), "year", "must not be in the future")
+}
+
+// fillMonths returns one entry for every month of year, using the counts in
+// months where there are any. Without a year the months are returned as is.
+func fillMonths(year int, months []MonthStats) []MonthStats {
+	if year == 0 {
+		return months
+	}
+
+	byMonth := make(map[string]MonthStats, len(months))
+	for _, m := range months {
+		byMonth[m.Month] = m
+	}
+
+	filled := make([]MonthStats, 12)
+	for i := range filled {
+		key := fmt.Sprintf("%04d-%02d", year, i+1)
+
+		filled[i] = byMonth[key]
+		filled[i].Month = key
+	}
+
+	return filled
+}
+
+type StatsModel struct {
+	DB *sql.DB
+}
+
+// statsPasses selects the finished read-throughs of user $1 that fall within
+// the optional bounds $2 and $3, along with the book that was read.
+const statsPasses = `
+    WITH passes AS (
+        SELECT rt.finished_at, rt.rating, b.id AS book_id, b.title, b.author, b.pages, b.genres
+        FROM read_throughs rt
+        INNER JOIN usersBooksRelation ub ON ub.id = rt.userbook_id
+        INNER JOIN books b ON b.id = ub.bookId
+        WHERE ub.userId = $1
+        AND rt.finished_at IS NOT NULL
+        AND ($2::timestamptz IS NULL OR rt.finished_at >= $2)
+        AND ($3::timestamptz IS NULL OR rt.finished_at < $3)
+    )`
+
+func (m StatsModel) ForUser(userID int64, year int) (*Stats, error) {
+	var from, to sql.NullTime
+	if year != 0 {
+		from = nullTime(time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC))
+		to = nullTime(from.Time.AddDate(1, 0, 0))
+	}
+
+	args := []any{userID, from, to}
+
+	stats := Stats{
+		Year:            year,
+		Genres:          []GenreStats{},
+		FavoriteAuthors: []AuthorStats{},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	query := statsPasses + `
+    SELECT count(*), COALESCE(sum(pages), 0), COALESCE(avg(rating) FILTER (WHERE rating > 0), 0)
+    FROM passes`
+
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&stats.BooksRead, &stats.PagesRead, &stats.AverageRating)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+    SELECT avg(extract(epoch FROM read_at - added_at) / 86400)
+    FROM usersBooksRelation
+    WHERE userId = $1
+    AND read AND read_at >= added_at
+    AND ($2::timestamptz IS NULL OR read_at >= $2)
+    AND ($3::timestamptz IS NULL OR read_at < $3)`
+
+	var days sql.NullFloat64
+
+	err = m.DB.QueryRowContext(ctx, query, args...).Scan(&days)
+	if err != nil {
+		return nil, err
+	}
+
+	if days.Valid {
+		stats.AverageDaysToRead = &days.Float64
+	}
+
+	query = statsPasses + `
+    SELECT to_char(finished_at AT TIME ZONE 'UTC', 'YYYY-MM'), count(*), sum(pages)
+    FROM passes
+    GROUP BY 1
+    ORDER BY 1`
+
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	months := []MonthStats{}
+
+	for rows.Next() {
+		var month MonthStats
+
+		err := rows.Scan(&month.Month, &month.BooksRead, &month.PagesRead)
+		if err != nil {
+			return nil, err
+		}
+
+		months = append(months, month)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	stats.Months = fillMonths(year, months)
+
+	query = statsPasses + `
+    SELECT genre, count(*)
+    FROM passes, unnest(genres) AS genre
+    GROUP BY genre
+    ORDER BY count(*) DESC, genre`
+
+	rows, err = m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var genre GenreStats
+
+		err := rows.Scan(&genre.Genre, &genre.BooksRead)
+		if err != nil {
+			return nil, err
+		}
+
+		stats.Genres = append(stats.Genres, genre)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = statsPasses + `
+    SELECT author, count(*), COALESCE(avg(rating) FILTER (WHERE rating > 0), 0)
+    FROM passes
+    GROUP BY author
+    ORDER BY 2 DESC, 3 DESC, author
+    LIMIT 5`
+
+	rows, err = m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var author AuthorStats
+
+		err := rows.Scan(&author.Author, &author.BooksRead, &author.AverageRating)
+		if err != nil {
+			return nil, err
+		}
+
+		stats.FavoriteAuthors = append(stats.FavoriteAuthors, author)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Books without a known page count are left out of the extremes.
+	for _, order := range []string{"DESC", "ASC"} {
+		query = statsPasses + fmt.Sprintf(`
+        SELECT book_id, title, author, pages
+        FROM passes
+        WHERE pages > 0
+        ORDER BY pages %s, book_id
+        LIMIT 1`, order)
+
+		var book StatsBook
+
+		err = m.DB.QueryRowContext(ctx, query, args...).Scan(&book.ID, &book.Title, &book.Author, &book.Pages)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return nil, err
+		}
+
+		if order == "DESC" {
+			stats.LongestBook = &book
+		} else {
+			stats.ShortestBook = &book
+		}
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+func TestStatsModel_ForUser(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := StatsModel{db}
+
+	stats, err := m.ForUser(1, 2024)
+	assert.NilError(t, err)
+
+	assert.Equal(t, stats.BooksRead, 1)
+	assert.Equal(t, stats.PagesRead, 700)
+	assert.Equal(t, stats.AverageRating, 4.5)
+	assert.Equal(t, len(stats.Months), 12)
+	assert.Equal(t, stats.Months[3].BooksRead, 1)
+	assert.Equal(t, len(stats.Genres), 2)
+	assert.Equal(t, stats.FavoriteAuthors[0].Author, "GRRM Martin")
+	assert.Equal(t, stats.LongestBook.ID, int64(2))
+
+	stats, err = m.ForUser(1, 2023)
+	assert.NilError(t, err)
+
+	assert.Equal(t, stats.BooksRead, 0)
+	assert.Equal(t, stats.LongestBook == nil, true)
+}
+
+func TestFillMonths(t *testing.T) {
+	months := fillMonths(2024, []MonthStats{{Month: "2024-04", BooksRead: 2, PagesRead: 900}})
+
+	assert.Equal(t, len(months), 12)
+	assert.Equal(t, months[0], MonthStats{Month: "2024-01"})
+	assert.Equal(t, months[3], MonthStats{Month: "2024-04", BooksRead: 2, PagesRead: 900})
+	assert.Equal(t, months[11].Month, "2024-12")
+
+	months = fillMonths(0, []MonthStats{{Month: "2019-02", BooksRead: 1}})
+
+	assert.Equal(t, len(months), 1)
+	assert.Equal(t, months[0], MonthStats{Month: "2019-02", BooksRead: 1})
+}
+
+func TestValidateStatsYear(t *testing.T) {
+	tests := []struct {
+		name      string
+		year      int
+		wantError map[string]string
+	}{
+		{name: "All time", year: 0, wantError: nil},
+		{name: "Valid year", year: 2024, wantError: nil},
+		{name: "Too early", year: 1066, wantError: map[string]string{"year": "must be greater than 1900"}},
+		{name: "In the future", year: 3000, wantError: map[string]string{"year": "must not be in the future"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validator.New()
+
+			ValidateStatsYear(v, tt.year)
+			assert.DeepEqual(t, tt.wantError, v.Errors)
+		})
+	}
+}
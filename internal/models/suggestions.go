@@ -0,0 +1,81 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	SuggestionTitle  = "title"
+	SuggestionAuthor = "author"
+)
+
+// Suggestion is a book title or an author name that matches what has been
+// typed into the search box so far. ID is the id of the book or the author.
+type Suggestion struct {
+	Type string `json:"type" example:"title"`
+	ID   int64  `json:"id"   example:"5"`
+	Text string `json:"text" example:"The Hobbit"`
+}
+
+// Suggest returns up to limit book titles and author names that contain a
+// word similar to q, so that typos and unfinished words still match. The
+// best matches come first, and among equal matches the ones on the most
+// shelves.
+func (b BookModel) Suggest(q string, limit int) ([]*Suggestion, error) {
+	query := `
+    (
+        SELECT 'title' AS type, id, title AS text, word_similarity($1, title) AS score,
+            (SELECT count(*) FROM usersBooksRelation ub WHERE ub.bookId = books.id) AS popularity
+        FROM books
+        WHERE $1 <% title AND deleted_at IS NULL
+        ORDER BY score DESC, popularity DESC
+        LIMIT $2
+    )
+    UNION ALL
+    (
+        SELECT 'author' AS type, id, name AS text, word_similarity($1, name) AS score,
+            (
+                SELECT count(*) FROM books_authors ba
+                INNER JOIN usersBooksRelation ub ON ub.bookId = ba.book_id
+                WHERE ba.author_id = authors.id
+            ) AS popularity
+        FROM authors
+        WHERE $1 <% name
+        ORDER BY score DESC, popularity DESC
+        LIMIT $2
+    )
+    ORDER BY score DESC, popularity DESC, text ASC
+    LIMIT $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := b.DB.QueryContext(ctx, query, q, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := []*Suggestion{}
+
+	for rows.Next() {
+		var (
+			suggestion Suggestion
+			score      float64
+			popularity int
+		)
+
+		err := rows.Scan(&suggestion.Type, &suggestion.ID, &suggestion.Text, &score, &popularity)
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions = append(suggestions, &suggestion)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
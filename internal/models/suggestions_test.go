@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+)
+
+func TestBookModel_Suggest(t *testing.T) {
+	tests := []struct {
+		name     string
+		q        string
+		wantType string
+		wantText string
+	}{
+		{name: "Unfinished title", q: "hobb", wantType: SuggestionTitle, wantText: "The Hobbit"},
+		{name: "Misspelled title", q: "hobit", wantType: SuggestionTitle, wantText: "The Hobbit"},
+		{name: "Misspelled author", q: "tolkin", wantType: SuggestionAuthor, wantText: "JRR Tolkien"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := NewTestDB(t)
+
+			m := BookModel{db}
+
+			suggestions, err := m.Suggest(tt.q, 5)
+			assert.NilError(t, err)
+
+			if len(suggestions) == 0 {
+				t.Fatalf("got no suggestions for %q", tt.q)
+			}
+
+			assert.Equal(t, suggestions[0].Type, tt.wantType)
+			assert.Equal(t, suggestions[0].Text, tt.wantText)
+		})
+	}
+}
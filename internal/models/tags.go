@@ -0,0 +1,241 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+var ErrDuplicateTagName = errors.New("duplicate tag name")
+
+// Tag is a personal label a user puts on their shelved books, like
+// "audiobook" or "lent to Sam". Unlike genres, tags are private to the user.
+type Tag struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"-"`
+	Name      string    `json:"name"       example:"audiobook"`
+	BookCount int       `json:"book_count"`
+	CreatedAt time.Time `json:"created_at"`
+	Version   int32     `json:"-"`
+}
+
+func ValidateTag(v *validator.Validator, tag *Tag) {
+	v.Check(strings.TrimSpace(tag.Name) != "", "name", "must be provided")
+	v.Check(len(tag.Name) <= 50, "name", "must not be more than 50 bytes long")
+}
+
+// ValidateTags checks the tags given for a shelved book.
+func ValidateTags(v *validator.Validator, tags []string) {
+	v.Check(len(tags) <= 20, "tags", "must not contain more than 20 tags")
+	v.Check(validator.Unique(tags), "tags", "must not contain duplicate values")
+
+	for _, tag := range tags {
+		v.Check(strings.TrimSpace(tag) != "", "tags", "must not contain blank values")
+		v.Check(len(tag) <= 50, "tags", "must not contain values more than 50 bytes long")
+	}
+}
+
+// userBookTagsColumn selects the tag names of a usersBooksRelation row.
+const userBookTagsColumn = `
+    ARRAY(
+        SELECT t.name FROM userbooks_tags ut
+        INNER JOIN tags t ON t.id = ut.tag_id
+        WHERE ut.userbook_id = usersBooksRelation.id
+        ORDER BY t.name
+    )`
+
+// setUserBookTags makes the tags of the shelved book exactly the given names,
+// creating the tags the user doesn't have yet.
+func setUserBookTags(ctx context.Context, tx *sql.Tx, userID, userBookID int64, tags []string) error {
+	query := `
+    WITH names AS (
+        SELECT DISTINCT unnest($3::text[]) AS name
+    ), created AS (
+        INSERT INTO tags (user_id, name)
+        SELECT $1, name FROM names
+        ON CONFLICT (user_id, name) DO NOTHING
+        RETURNING id
+    ), wanted AS (
+        SELECT id FROM created
+        UNION ALL
+        SELECT t.id FROM tags t INNER JOIN names n ON n.name = t.name WHERE t.user_id = $1
+    ), removed AS (
+        DELETE FROM userbooks_tags
+        WHERE userbook_id = $2 AND tag_id NOT IN (SELECT id FROM wanted)
+    )
+    INSERT INTO userbooks_tags (userbook_id, tag_id)
+    SELECT $2, id FROM wanted
+    ON CONFLICT DO NOTHING`
+
+	_, err := tx.ExecContext(ctx, query, userID, userBookID, pq.Array(tags))
+	return err
+}
+
+type TagModel struct {
+	DB *sql.DB
+}
+
+func (m TagModel) Insert(tag *Tag) error {
+	query := `
+    INSERT INTO tags (user_id, name)
+    VALUES ($1, $2)
+    RETURNING id, created_at, version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, tag.UserID, tag.Name).Scan(&tag.ID, &tag.CreatedAt, &tag.Version)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "tags_user_id_name_key"):
+			return ErrDuplicateTagName
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m TagModel) GetForUser(userID, id int64) (*Tag, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+    SELECT t.id, t.user_id, t.name, count(ut.userbook_id), t.created_at, t.version
+    FROM tags t
+    LEFT JOIN userbooks_tags ut ON ut.tag_id = t.id
+    WHERE t.id = $1 AND t.user_id = $2
+    GROUP BY t.id`
+
+	var tag Tag
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, id, userID).Scan(
+		&tag.ID,
+		&tag.UserID,
+		&tag.Name,
+		&tag.BookCount,
+		&tag.CreatedAt,
+		&tag.Version,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// ListForUser returns the tags of the user with the number of books carrying
+// each, the most used first.
+func (m TagModel) ListForUser(userID int64) ([]*Tag, error) {
+	query := `
+    SELECT t.id, t.user_id, t.name, count(ut.userbook_id), t.created_at, t.version
+    FROM tags t
+    LEFT JOIN userbooks_tags ut ON ut.tag_id = t.id
+    WHERE t.user_id = $1
+    GROUP BY t.id
+    ORDER BY count(ut.userbook_id) DESC, t.name ASC, t.id ASC`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []*Tag{}
+
+	for rows.Next() {
+		var tag Tag
+
+		err := rows.Scan(
+			&tag.ID,
+			&tag.UserID,
+			&tag.Name,
+			&tag.BookCount,
+			&tag.CreatedAt,
+			&tag.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, &tag)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (m TagModel) Update(tag *Tag) error {
+	query := `
+    UPDATE tags
+    SET name = $1, version = version + 1
+    WHERE id = $2 AND version = $3
+    RETURNING version`
+
+	args := []any{tag.Name, tag.ID, tag.Version}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&tag.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEditConflict
+		case strings.Contains(err.Error(), "tags_user_id_name_key"):
+			return ErrDuplicateTagName
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteForUser removes the tag from the user and from all their books.
+func (m TagModel) DeleteForUser(userID, id int64) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	query := `
+    DELETE FROM tags
+    WHERE id = $1 AND user_id = $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
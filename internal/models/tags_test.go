@@ -0,0 +1,80 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+func TestTagModel_ListForUser(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := TagModel{db}
+
+	tags, err := m.ListForUser(1)
+	assert.NilError(t, err)
+	assert.Equal(t, len(tags), 2)
+	assert.Equal(t, tags[0].Name, "audiobook")
+	assert.Equal(t, tags[0].BookCount, 1)
+	assert.Equal(t, tags[1].BookCount, 0)
+
+	tags, err = m.ListForUser(2)
+	assert.NilError(t, err)
+	assert.Equal(t, len(tags), 0)
+}
+
+func TestUserBookModel_Tags(t *testing.T) {
+	db := NewTestDB(t)
+
+	userBooks := UserBookModel{db}
+	tags := TagModel{db}
+
+	userBook, err := userBooks.GetForUser(1, 2)
+	assert.NilError(t, err)
+	assert.Equal(t, len(userBook.Tags), 1)
+
+	userBook.Tags = []string{"lent to Sam", "reread-worthy"}
+	assert.NilError(t, userBooks.Update(userBook))
+
+	userBook, err = userBooks.GetForUser(1, 2)
+	assert.NilError(t, err)
+	assert.Equal(t, len(userBook.Tags), 2)
+	assert.Equal(t, userBook.Tags[1], "reread-worthy")
+
+	list, err := tags.ListForUser(1)
+	assert.NilError(t, err)
+	assert.Equal(t, len(list), 3)
+
+	filters := Filters{Page: 1, PageSize: 20, Sort: "id", SortSafeList: []string{"id"}}
+
+	found, _, err := userBooks.ListForUser(1, UserBookFilters{Tags: []string{"lent to Sam", "reread-worthy"}}, filters)
+	assert.NilError(t, err)
+	assert.Equal(t, len(found), 1)
+
+	found, _, err = userBooks.ListForUser(1, UserBookFilters{Tags: []string{"audiobook"}}, filters)
+	assert.NilError(t, err)
+	assert.Equal(t, len(found), 0)
+}
+
+func TestValidateTags(t *testing.T) {
+	tests := []struct {
+		name      string
+		tags      []string
+		wantError map[string]string
+	}{
+		{name: "Valid", tags: []string{"audiobook", "lent to Sam"}, wantError: nil},
+		{name: "Duplicate", tags: []string{"audiobook", "audiobook"}, wantError: map[string]string{"tags": "must not contain duplicate values"}},
+		{name: "Blank", tags: []string{" "}, wantError: map[string]string{"tags": "must not contain blank values"}},
+		{name: "Too long", tags: []string{strings.Repeat("a", 51)}, wantError: map[string]string{"tags": "must not contain values more than 50 bytes long"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validator.New()
+			ValidateTags(v, tt.tags)
+			assert.DeepEqual(t, tt.wantError, v.Errors)
+		})
+	}
+}
@@ -1,3 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
 CREATE TABLE IF NOT EXISTS books (
     id bigserial PRIMARY KEY,  
     created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
@@ -13,9 +15,83 @@ ALTER TABLE books ADD CONSTRAINT books_page_check CHECK (pages >= 0);
 ALTER TABLE books ADD CONSTRAINT books_year_check CHECK (year BETWEEN 1 and date_part('year', now()));
 ALTER TABLE books ADD CONSTRAINT genres_length_check CHECK (array_length(genres, 1) BETWEEN 1 AND 10);
 
-CREATE INDEX IF NOT EXISTS books_title_idx ON books USING GIN (to_tsvector('simple', title));
+ALTER TABLE books ADD COLUMN IF NOT EXISTS isbn text;
+ALTER TABLE books ADD COLUMN IF NOT EXISTS publisher text NOT NULL DEFAULT '';
+ALTER TABLE books ADD COLUMN IF NOT EXISTS language text NOT NULL DEFAULT '';
+ALTER TABLE books ADD COLUMN IF NOT EXISTS description text NOT NULL DEFAULT '';
+ALTER TABLE books ADD COLUMN IF NOT EXISTS cover_url text NOT NULL DEFAULT '';
+ALTER TABLE books ADD COLUMN IF NOT EXISTS original_title text NOT NULL DEFAULT '';
+
+ALTER TABLE books ADD CONSTRAINT books_isbn_check CHECK (isbn ~ '^97[89][0-9]{10}$');
+ALTER TABLE books ADD CONSTRAINT books_language_check CHECK (language ~ '^([a-z]{2,3})?$');
+
+CREATE OR REPLACE FUNCTION books_search_vector(title text, author text, genres text[], description text)
+RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('simple', title), 'A')
+        || setweight(to_tsvector('simple', author), 'B')
+        || setweight(to_tsvector('simple', array_to_string(genres, ' ')), 'C')
+        || setweight(to_tsvector('simple', description), 'D')
+$$ LANGUAGE sql IMMUTABLE;
+
+ALTER TABLE books ADD COLUMN IF NOT EXISTS search tsvector
+    GENERATED ALWAYS AS (books_search_vector(title, author, genres, description)) STORED;
+
+CREATE INDEX IF NOT EXISTS books_search_idx ON books USING GIN (search);
 CREATE INDEX IF NOT EXISTS books_genres_idx ON books USING GIN (genres);
 
+CREATE TABLE IF NOT EXISTS works (
+    id bigserial PRIMARY KEY,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE books ADD COLUMN IF NOT EXISTS work_id bigint NOT NULL REFERENCES works;
+
+CREATE INDEX IF NOT EXISTS books_work_id_idx ON books (work_id);
+
+ALTER TABLE books ADD COLUMN IF NOT EXISTS title_key text
+    GENERATED ALWAYS AS (lower(regexp_replace(title, '[^[:alnum:]]+', '', 'g'))) STORED;
+ALTER TABLE books ADD COLUMN IF NOT EXISTS author_key text
+    GENERATED ALWAYS AS (lower(regexp_replace(author, '[^[:alnum:]]+', '', 'g'))) STORED;
+
+CREATE INDEX IF NOT EXISTS books_title_key_author_key_idx ON books (title_key, author_key);
+
+ALTER TABLE books ADD COLUMN IF NOT EXISTS deleted_at timestamp(0) with time zone;
+
+CREATE INDEX IF NOT EXISTS books_deleted_at_idx ON books (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS books_isbn_key ON books (isbn) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS books_title_trgm_idx ON books USING GIN (title gin_trgm_ops) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS books_year_idx ON books (year) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS books_pages_idx ON books (pages) WHERE deleted_at IS NULL;
+
+CREATE OR REPLACE FUNCTION books_work() RETURNS trigger AS $$
+BEGIN
+    IF NEW.work_id IS NULL THEN
+        INSERT INTO works DEFAULT VALUES RETURNING id INTO NEW.work_id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER books_work
+BEFORE INSERT ON books
+FOR EACH ROW EXECUTE FUNCTION books_work();
+
+CREATE OR REPLACE FUNCTION books_work_cleanup() RETURNS trigger AS $$
+BEGIN
+    DELETE FROM works
+    WHERE id = OLD.work_id AND NOT EXISTS (SELECT 1 FROM books WHERE work_id = OLD.work_id);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER books_work_cleanup
+AFTER DELETE OR UPDATE OF work_id ON books
+FOR EACH ROW EXECUTE FUNCTION books_work_cleanup();
+
 CREATE TABLE IF NOT EXISTS users (
     id bigserial PRIMARY KEY,
     created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
@@ -36,15 +112,373 @@ CREATE TABLE IF NOT EXISTS usersBooksRelation (
   read_at timestamp(0) with time zone,
   reviewed_at timestamp(0) with time zone,
   version int NOT NULL DEFAULT 1,
+  helpful_count integer NOT NULL DEFAULT 0,
+  status text NOT NULL DEFAULT 'want-to-read' CHECK (status IN ('want-to-read', 'reading', 'read', 'abandoned')),
+  current_page integer NOT NULL DEFAULT 0 CHECK (current_page >= 0),
 
   FOREIGN KEY (bookId) REFERENCES books(id),
   FOREIGN KEY (userId) REFERENCES users(id),
   UNIQUE (bookId, userId)
 );
 
+CREATE TABLE IF NOT EXISTS reading_progress (
+    id bigserial PRIMARY KEY,
+    userbook_id bigint NOT NULL REFERENCES usersBooksRelation ON DELETE CASCADE,
+    page integer NOT NULL CHECK (page >= 0),
+    percentage real NOT NULL CHECK (percentage BETWEEN 0 AND 100),
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS read_throughs (
+    id bigserial PRIMARY KEY,
+    userbook_id bigint NOT NULL REFERENCES usersBooksRelation ON DELETE CASCADE,
+    started_at timestamp(0) with time zone,
+    finished_at timestamp(0) with time zone,
+    rating real NOT NULL DEFAULT 0 CHECK (rating BETWEEN 0 AND 5),
+    notes text NOT NULL DEFAULT '',
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS read_throughs_userbook_id_idx ON read_throughs (userbook_id);
+CREATE INDEX IF NOT EXISTS read_throughs_finished_at_idx ON read_throughs (finished_at);
+
+-- usersbooksrelation_read_throughs opens a read-through when a book is
+-- started and finishes the open one (or records a new one) when it is read.
+-- A re-read doesn't take over the read_at and rating of the pass before it:
+-- unless they are changed along with the status, it finishes now and unrated.
+CREATE OR REPLACE FUNCTION usersbooksrelation_read_throughs() RETURNS trigger AS $$
+DECLARE
+    reread boolean;
+    finished timestamp(0) with time zone;
+    finished_rating real;
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.status = OLD.status THEN
+        RETURN NULL;
+    END IF;
+
+    -- Merging two books moves the read-throughs of the duplicate entry along
+    -- with its status, so they must not be recorded a second time.
+    IF current_setting('bookshelf.merging_books', true) = 'on' THEN
+        RETURN NULL;
+    END IF;
+
+    IF NEW.status = 'reading' THEN
+        INSERT INTO read_throughs (userbook_id, started_at)
+        SELECT NEW.id, NOW()
+        WHERE NOT EXISTS (SELECT 1 FROM read_throughs WHERE userbook_id = NEW.id AND finished_at IS NULL);
+    ELSIF NEW.status = 'read' THEN
+        reread := TG_OP = 'UPDATE' AND EXISTS (
+            SELECT 1 FROM read_throughs WHERE userbook_id = NEW.id AND finished_at IS NOT NULL
+        );
+
+        finished := CASE
+            WHEN reread AND NEW.read_at IS NOT DISTINCT FROM OLD.read_at THEN NOW()
+            WHEN NEW.read_at >= '1900-01-01' THEN NEW.read_at
+            ELSE NOW()
+        END;
+        finished_rating := CASE
+            WHEN reread AND NEW.rating IS NOT DISTINCT FROM OLD.rating THEN 0
+            ELSE COALESCE(NEW.rating, 0)
+        END;
+
+        UPDATE read_throughs
+        SET finished_at = finished, rating = finished_rating, version = version + 1
+        WHERE id = (
+            SELECT id FROM read_throughs
+            WHERE userbook_id = NEW.id AND finished_at IS NULL
+            ORDER BY started_at DESC NULLS LAST, id DESC
+            LIMIT 1
+        );
+
+        IF NOT FOUND THEN
+            INSERT INTO read_throughs (userbook_id, finished_at, rating)
+            VALUES (NEW.id, finished, finished_rating);
+        END IF;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER usersbooksrelation_read_throughs
+AFTER INSERT OR UPDATE OF status ON usersBooksRelation
+FOR EACH ROW EXECUTE FUNCTION usersbooksrelation_read_throughs();
+
+CREATE TABLE IF NOT EXISTS shelves (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    name text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1,
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS shelves_books (
+    shelf_id bigint NOT NULL REFERENCES shelves ON DELETE CASCADE,
+    userbook_id bigint NOT NULL REFERENCES usersBooksRelation ON DELETE CASCADE,
+    position integer NOT NULL DEFAULT 0 CHECK (position >= 0),
+    shelved_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (shelf_id, userbook_id)
+);
+
+CREATE INDEX IF NOT EXISTS shelves_books_userbook_id_idx ON shelves_books (userbook_id);
+
+CREATE TABLE IF NOT EXISTS goals (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    year integer NOT NULL,
+    target_books integer NOT NULL DEFAULT 0 CHECK (target_books >= 0),
+    target_pages integer NOT NULL DEFAULT 0 CHECK (target_pages >= 0),
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1,
+    UNIQUE (user_id, year),
+    CONSTRAINT goals_target_check CHECK (target_books > 0 OR target_pages > 0)
+);
+
+CREATE TABLE IF NOT EXISTS imports (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    file_hash bytea NOT NULL,
+    source text NOT NULL,
+    status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    total_rows integer NOT NULL DEFAULT 0,
+    processed integer NOT NULL DEFAULT 0,
+    matched integer NOT NULL DEFAULT 0,
+    created integer NOT NULL DEFAULT 0,
+    failed integer NOT NULL DEFAULT 0,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    finished_at timestamp(0) with time zone,
+    UNIQUE (user_id, file_hash)
+);
+
+ALTER TABLE imports ADD COLUMN IF NOT EXISTS updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW();
+
+CREATE TABLE IF NOT EXISTS import_failures (
+    import_id bigint NOT NULL REFERENCES imports ON DELETE CASCADE,
+    row_number integer NOT NULL,
+    title text NOT NULL DEFAULT '',
+    reason text NOT NULL,
+    PRIMARY KEY (import_id, row_number)
+);
+
+-- name_key folds punctuation, spacing and case, so that "J.R.R. Tolkien" and
+-- "J. R. R. Tolkien" are the same author.
+CREATE TABLE IF NOT EXISTS authors (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    name_key text GENERATED ALWAYS AS (lower(regexp_replace(name, '[^[:alnum:]]+', '', 'g'))) STORED,
+    bio text NOT NULL DEFAULT '',
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1,
+    CONSTRAINT authors_name_key_key UNIQUE (name_key)
+);
+
+CREATE TABLE IF NOT EXISTS books_authors (
+    book_id bigint NOT NULL REFERENCES books ON DELETE CASCADE,
+    author_id bigint NOT NULL REFERENCES authors ON DELETE CASCADE,
+    role text NOT NULL DEFAULT 'author' CHECK (role IN ('author', 'translator', 'editor', 'illustrator')),
+    position integer NOT NULL DEFAULT 0,
+    PRIMARY KEY (book_id, author_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS books_authors_author_id_idx ON books_authors (author_id);
+CREATE INDEX IF NOT EXISTS authors_name_trgm_idx ON authors USING GIN (name gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS series (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS series_books (
+    series_id bigint NOT NULL REFERENCES series ON DELETE CASCADE,
+    book_id bigint NOT NULL REFERENCES books ON DELETE CASCADE,
+    position numeric(6, 2) NOT NULL CHECK (position >= 0),
+    PRIMARY KEY (series_id, book_id)
+);
+
+CREATE INDEX IF NOT EXISTS series_books_book_id_idx ON series_books (book_id);
+CREATE INDEX IF NOT EXISTS series_books_position_idx ON series_books (series_id, position);
+
+-- name_key folds punctuation, spacing and case the same way authors do, so
+-- that "Sci-Fi" and "SciFi" are one genre.
+CREATE TABLE IF NOT EXISTS genres (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    name_key text GENERATED ALWAYS AS (lower(regexp_replace(name, '[^[:alnum:]]+', '', 'g'))) STORED,
+    parent_id bigint REFERENCES genres ON DELETE SET NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1,
+    CONSTRAINT genres_name_key_key UNIQUE (name_key),
+    CONSTRAINT genres_parent_id_check CHECK (parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS genres_parent_id_idx ON genres (parent_id);
+
+-- genre_aliases are other spellings of a genre, like "SF" for "Science
+-- Fiction". Books are always stored with the canonical name.
+CREATE TABLE IF NOT EXISTS genre_aliases (
+    alias text NOT NULL,
+    alias_key text GENERATED ALWAYS AS (lower(regexp_replace(alias, '[^[:alnum:]]+', '', 'g'))) STORED,
+    genre_id bigint NOT NULL REFERENCES genres ON DELETE CASCADE,
+    CONSTRAINT genre_aliases_alias_key_key UNIQUE (alias_key)
+);
+
+CREATE INDEX IF NOT EXISTS genre_aliases_genre_id_idx ON genre_aliases (genre_id);
+
+-- genre_subtree returns the given genres together with all genres below them.
+-- Names that aren't genres are kept as they are.
+CREATE OR REPLACE FUNCTION genre_subtree(names text[]) RETURNS text[] AS $$
+    WITH RECURSIVE tree AS (
+        SELECT id, name
+        FROM genres
+        WHERE name_key IN (SELECT lower(regexp_replace(n, '[^[:alnum:]]+', '', 'g')) FROM unnest(names) n)
+        UNION
+        SELECT g.id, g.name
+        FROM genres g
+        INNER JOIN tree t ON g.parent_id = t.id
+    )
+    SELECT names || COALESCE(array_agg(name), '{}') FROM tree
+$$ LANGUAGE sql STABLE;
+
+CREATE TABLE IF NOT EXISTS tags (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    name text NOT NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1,
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS userbooks_tags (
+    userbook_id bigint NOT NULL REFERENCES usersBooksRelation ON DELETE CASCADE,
+    tag_id bigint NOT NULL REFERENCES tags ON DELETE CASCADE,
+    PRIMARY KEY (userbook_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS userbooks_tags_tag_id_idx ON userbooks_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id bigserial PRIMARY KEY,
+    code text NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS users_permissions (
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    permission_id bigint NOT NULL REFERENCES permissions ON DELETE CASCADE,
+    PRIMARY KEY (user_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS tokens (
+    id bigserial PRIMARY KEY,
+    hash bytea NOT NULL UNIQUE,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    expiry timestamp(0) with time zone NOT NULL,
+    scope text NOT NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO permissions (code) VALUES ('books:read'), ('books:write'), ('books:admin');
+
+CREATE TABLE IF NOT EXISTS review_helpful_votes (
+    review_id bigint NOT NULL REFERENCES usersBooksRelation ON DELETE CASCADE,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (review_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS book_rating_stats (
+    book_id bigint PRIMARY KEY REFERENCES books ON DELETE CASCADE,
+    ratings_count integer NOT NULL DEFAULT 0,
+    ratings_sum double precision NOT NULL DEFAULT 0,
+    histogram integer[] NOT NULL DEFAULT '{0,0,0,0,0}'
+);
+
+-- book_rating_stats_apply adds (sign = 1) or removes (sign = -1) a single
+-- rating from the aggregates of a book. Unrated entries are stored as 0.
+CREATE OR REPLACE FUNCTION book_rating_stats_apply(book bigint, rating real, sign integer) RETURNS void AS $$
+DECLARE
+    star integer;
+BEGIN
+    IF rating IS NULL OR rating <= 0 THEN
+        RETURN;
+    END IF;
+
+    star := LEAST(5, GREATEST(1, round(rating::numeric)::integer));
+
+    INSERT INTO book_rating_stats (book_id) VALUES (book) ON CONFLICT (book_id) DO NOTHING;
+
+    UPDATE book_rating_stats
+    SET ratings_count = ratings_count + sign,
+        ratings_sum = ratings_sum + sign * rating,
+        histogram[star] = histogram[star] + sign
+    WHERE book_id = book;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION usersbooksrelation_rating_stats() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM book_rating_stats_apply(OLD.bookId, OLD.rating, -1);
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM book_rating_stats_apply(NEW.bookId, NEW.rating, 1);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER usersbooksrelation_rating_stats
+AFTER INSERT OR DELETE OR UPDATE OF rating, bookId ON usersBooksRelation
+FOR EACH ROW EXECUTE FUNCTION usersbooksrelation_rating_stats();
+
+CREATE OR REPLACE FUNCTION review_helpful_votes_count() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE usersBooksRelation SET helpful_count = helpful_count + 1 WHERE id = NEW.review_id;
+    ELSE
+        UPDATE usersBooksRelation SET helpful_count = helpful_count - 1 WHERE id = OLD.review_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER review_helpful_votes_count
+AFTER INSERT OR DELETE ON review_helpful_votes
+FOR EACH ROW EXECUTE FUNCTION review_helpful_votes_count();
+
 INSERT INTO users (id, name, avatar, provider) VALUES (1, 'Alice Jones', 'avat', 'discord');
-INSERT INTO books (id, title, author, year, pages, genres) VALUES (1, 'The Hobbit', 'JRR Tolkien', 1890, 320, ARRAY ['Fantasy', 'Childrens Literature']);
+INSERT INTO books (id, title, author, year, pages, genres, isbn) VALUES (1, 'The Hobbit', 'JRR Tolkien', 1890, 320, ARRAY ['Fantasy', 'Childrens Literature'], '9780261103573');
 INSERT INTO books (id, title, author, year, pages, genres) VALUES (2, 'A Game Of Thrones', 'GRRM Martin', 1990, 700, ARRAY ['Fantasy', 'Epic']);
 
-INSERT INTO usersBooksRelation (id, bookId, userId, read, rating, reviewBody, read_at, reviewed_at, version)
-VALUES (14, 2, 1, true, 4.5, 'Very good book yes!', '2024-04-10 14:30:00', '2024-04-11 15:00:00', 1);
+INSERT INTO authors (name) VALUES ('JRR Tolkien'), ('GRRM Martin');
+INSERT INTO books_authors (book_id, author_id) VALUES (1, 1), (2, 2);
+
+INSERT INTO genres (name) VALUES ('Fiction'), ('Childrens Literature'), ('Uncategorized');
+INSERT INTO genres (name, parent_id) SELECT 'Fantasy', id FROM genres WHERE name = 'Fiction';
+INSERT INTO genres (name, parent_id) SELECT 'Science Fiction', id FROM genres WHERE name = 'Fiction';
+INSERT INTO genres (name, parent_id) SELECT 'Epic', id FROM genres WHERE name = 'Fantasy';
+INSERT INTO genre_aliases (alias, genre_id) SELECT 'Sci-Fi', id FROM genres WHERE name = 'Science Fiction';
+INSERT INTO genre_aliases (alias, genre_id) SELECT 'SF', id FROM genres WHERE name = 'Science Fiction';
+
+INSERT INTO series (name) VALUES ('Test Saga');
+INSERT INTO series_books (series_id, book_id, position) VALUES (1, 2, 1), (1, 1, 1.5);
+
+INSERT INTO usersBooksRelation (id, bookId, userId, read, status, rating, reviewBody, read_at, reviewed_at, version)
+VALUES (14, 2, 1, true, 'read', 4.5, 'Very good book yes!', '2024-04-10 14:30:00', '2024-04-11 15:00:00', 1);
+
+INSERT INTO users_permissions
+SELECT 1, permissions.id FROM permissions WHERE permissions.code = 'books:read';
+
+INSERT INTO shelves (id, user_id, name) VALUES (3, 1, 'Favorites');
+INSERT INTO shelves_books (shelf_id, userbook_id, position) VALUES (3, 14, 0);
+
+INSERT INTO tags (user_id, name) VALUES (1, 'audiobook'), (1, 'lent to Sam');
+INSERT INTO userbooks_tags (userbook_id, tag_id) SELECT 14, id FROM tags WHERE name = 'audiobook';
@@ -1,3 +1,32 @@
+DROP TABLE userbooks_tags;
+DROP TABLE tags;
+DROP TABLE genre_aliases;
+DROP TABLE genres;
+DROP TABLE series_books;
+DROP TABLE series;
+DROP TABLE books_authors;
+DROP TABLE authors;
+DROP TABLE import_failures;
+DROP TABLE imports;
+DROP TABLE goals;
+DROP TABLE shelves_books;
+DROP TABLE shelves;
+DROP TABLE read_throughs;
+DROP TABLE reading_progress;
+DROP TABLE review_helpful_votes;
+DROP TABLE book_rating_stats;
+DROP TABLE tokens;
+DROP TABLE users_permissions;
+DROP TABLE permissions;
 DROP TABLE usersBooksRelation;
 DROP TABLE users;
 DROP TABLE books;
+DROP TABLE works;
+DROP FUNCTION review_helpful_votes_count();
+DROP FUNCTION usersbooksrelation_rating_stats();
+DROP FUNCTION book_rating_stats_apply(bigint, real, integer);
+DROP FUNCTION usersbooksrelation_read_throughs();
+DROP FUNCTION books_work_cleanup();
+DROP FUNCTION books_work();
+DROP FUNCTION books_search_vector(text, text, text[], text);
+DROP FUNCTION genre_subtree(text[]);
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+	"github.com/svenrisse/bookshelf/internal/validator"
+)
+
+func TestGenerateToken(t *testing.T) {
+	token, err := generateToken(1, time.Hour, ScopeAuthentication)
+	assert.NilError(t, err)
+
+	v := validator.New()
+	ValidateTokenPlaintext(v, token.Plaintext)
+
+	assert.Equal(t, v.Valid(), true)
+	assert.Equal(t, len(token.Hash), 32)
+	assert.Equal(t, token.Scope, ScopeAuthentication)
+}
+
+func TestTokenModel_GetForToken(t *testing.T) {
+	db := NewTestDB(t)
+
+	tokens := TokenModel{db}
+	users := UserModel{db}
+
+	token, err := tokens.New(1, time.Hour, ScopeAuthentication)
+	assert.NilError(t, err)
+
+	user, err := users.GetForToken(ScopeAuthentication, token.Plaintext)
+	assert.NilError(t, err)
+	assert.Equal(t, user.ID, 1)
+
+	err = tokens.DeleteForUser(1, token.ID)
+	assert.NilError(t, err)
+
+	_, err = users.GetForToken(ScopeAuthentication, token.Plaintext)
+	assert.Equal(t, err, ErrRecordNotFound)
+}
@@ -4,22 +4,54 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/svenrisse/bookshelf/internal/validator"
 )
 
+var ErrDuplicateUserBook = errors.New("duplicate user book")
+
+const (
+	StatusWantToRead = "want-to-read"
+	StatusReading    = "reading"
+	StatusRead       = "read"
+	StatusAbandoned  = "abandoned"
+)
+
+var Statuses = []string{StatusWantToRead, StatusReading, StatusRead, StatusAbandoned}
+
 type UserBook struct {
 	ID         int64     `json:"-"`
 	BookID     int64     `json:"book_id"`
 	UserID     int64     `json:"user_id"`
 	Read       bool      `json:"read"`
+	Status     string    `json:"status"`
+	Page       int32     `json:"current_page"`
 	Rating     float32   `json:"rating"`
 	ReviewBody string    `json:"review_body"`
 	CreatedAt  time.Time `json:"-"`
 	ReadAt     time.Time `json:"read_at"`
 	ReviewedAt time.Time `json:"reviewed_at"`
-	Version    int32     `json:"-"`
+	Tags       []string  `json:"tags"`
+	Version    int32     `json:"version"`
+}
+
+// SetStatus moves the entry to the given reading status and keeps the Read flag
+// and the ReadAt timestamp in line with it. An existing entry that moves into
+// read from another status is finished again, so ReadAt becomes now; callers
+// set a read_at given by the client afterwards.
+func (userBook *UserBook) SetStatus(status string) {
+	finished := status == StatusRead && userBook.Status != StatusRead
+
+	if finished && (userBook.Status != "" || userBook.ReadAt.IsZero()) {
+		userBook.ReadAt = time.Now()
+	}
+
+	userBook.Status = status
+	userBook.Read = status == StatusRead
 }
 
 type UserBookModel struct {
@@ -33,6 +65,12 @@ func ValidateUserBook(v *validator.Validator, userBook *UserBook) {
 	v.Check(userBook.BookID != 0, "BookID", "must be provided")
 	v.Check(userBook.BookID > 0, "BookID", "must be a positive integer")
 
+	v.Check(userBook.Rating >= 0, "rating", "must not be negative")
+	v.Check(userBook.Rating <= 5, "rating", "must not be greater than 5")
+
+	v.Check(validator.PermittedValue(userBook.Status, Statuses...), "status", "invalid status value")
+	v.Check(userBook.Page >= 0, "current_page", "must not be negative")
+
 	if len(userBook.ReviewBody) != 0 {
 		v.Check(len(userBook.ReviewBody) <= 5000, "reviewBody", "must be less than 5000 characters")
 		v.Check(userBook.Rating != 0, "rating", "if given reviewBody, rating must be provided")
@@ -51,28 +89,56 @@ func ValidateUserBook(v *validator.Validator, userBook *UserBook) {
 			"must not be in the future",
 		)
 	}
+
+	ValidateTags(v, userBook.Tags)
 }
 
 func (ub UserBookModel) Insert(userBook *UserBook) error {
 	query := `
-    INSERT INTO usersBooksRelation (bookId, userId, read, rating, reviewBody, read_at, reviewed_at)
-    VALUES ($1, $2, $3, $4, $5, $6, $7)
-    RETURNING id, added_at`
+    INSERT INTO usersBooksRelation (bookId, userId, read, status, current_page, rating, reviewBody, read_at, reviewed_at, added_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10, NOW()))
+    RETURNING id, added_at, version`
 
 	args := []any{
 		userBook.BookID,
 		userBook.UserID,
 		userBook.Read,
+		userBook.Status,
+		userBook.Page,
 		userBook.Rating,
 		userBook.ReviewBody,
 		userBook.ReadAt,
 		userBook.ReviewedAt,
+		nullTime(userBook.CreatedAt),
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	return ub.DB.QueryRowContext(ctx, query, args...).Scan(&userBook.ID, &userBook.CreatedAt)
+	tx, err := ub.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&userBook.ID, &userBook.CreatedAt, &userBook.Version)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "usersbooksrelation_bookid_userid_key"):
+			return ErrDuplicateUserBook
+		default:
+			return err
+		}
+	}
+
+	if userBook.Tags != nil {
+		err = setUserBookTags(ctx, tx, userBook.UserID, userBook.ID, userBook.Tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (ub UserBookModel) Get(id int64) (*UserBook, error) {
@@ -80,7 +146,7 @@ func (ub UserBookModel) Get(id int64) (*UserBook, error) {
 		return nil, ErrRecordNotFound
 	}
 	query := `
-    SELECT id, bookId, userId, read, rating, reviewBody, added_at, read_at, reviewed_at, version
+    SELECT id, bookId, userId, read, status, current_page, rating, reviewBody, added_at, read_at, reviewed_at, version,` + userBookTagsColumn + `
     FROM usersBooksRelation
     WHERE id = $1`
 
@@ -94,11 +160,54 @@ func (ub UserBookModel) Get(id int64) (*UserBook, error) {
 		&userBook.BookID,
 		&userBook.UserID,
 		&userBook.Read,
+		&userBook.Status,
+		&userBook.Page,
+		&userBook.Rating,
+		&userBook.ReviewBody,
+		&userBook.CreatedAt,
+		&userBook.ReadAt,
+		&userBook.ReviewedAt,
+		&userBook.Version,
+		pq.Array(&userBook.Tags))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return &userBook, nil
+}
+
+func (ub UserBookModel) GetForUser(userID, bookID int64) (*UserBook, error) {
+	if userID < 1 || bookID < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+    SELECT id, bookId, userId, read, status, current_page, rating, reviewBody, added_at, read_at, reviewed_at, version,` + userBookTagsColumn + `
+    FROM usersBooksRelation
+    WHERE userId = $1 AND bookId = $2`
+
+	var userBook UserBook
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := ub.DB.QueryRowContext(ctx, query, userID, bookID).Scan(
+		&userBook.ID,
+		&userBook.BookID,
+		&userBook.UserID,
+		&userBook.Read,
+		&userBook.Status,
+		&userBook.Page,
 		&userBook.Rating,
 		&userBook.ReviewBody,
+		&userBook.CreatedAt,
 		&userBook.ReadAt,
 		&userBook.ReviewedAt,
-		&userBook.Version)
+		&userBook.Version,
+		pq.Array(&userBook.Tags))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrRecordNotFound
@@ -112,18 +221,32 @@ func (ub UserBookModel) Get(id int64) (*UserBook, error) {
 func (ub UserBookModel) Update(userBook *UserBook) error {
 	query := `
     UPDATE usersBooksRelation 
-    SET read = $1, rating = $2::REAL, reviewBody = $3, read_at = $4, reviewed_at = $5, version = version + 1
-    WHERE id = $6 AND version = $7
+    SET read = $1, status = $2, current_page = $3, rating = $4::REAL, reviewBody = $5, read_at = $6, reviewed_at = $7, version = version + 1
+    WHERE id = $8 AND version = $9
     RETURNING version`
 
 	args := []any{
-		userBook.Read, userBook.Rating, userBook.ReviewBody, userBook.ReadAt, userBook.ReviewedAt, userBook.ID, userBook.Version,
+		userBook.Read,
+		userBook.Status,
+		userBook.Page,
+		userBook.Rating,
+		userBook.ReviewBody,
+		userBook.ReadAt,
+		userBook.ReviewedAt,
+		userBook.ID,
+		userBook.Version,
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err := ub.DB.QueryRowContext(ctx, query, args...).Scan(&userBook.Version)
+	tx, err := ub.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&userBook.Version)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return ErrEditConflict
@@ -131,6 +254,41 @@ func (ub UserBookModel) Update(userBook *UserBook) error {
 		return err
 	}
 
+	if userBook.Tags != nil {
+		err = setUserBookTags(ctx, tx, userBook.UserID, userBook.ID, userBook.Tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (ub UserBookModel) DeleteForUser(userID, bookID int64) error {
+	if userID < 1 || bookID < 1 {
+		return ErrRecordNotFound
+	}
+
+	query := `DELETE FROM usersBooksRelation
+    WHERE userId = $1 AND bookId = $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := ub.DB.ExecContext(ctx, query, userID, bookID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
 	return nil
 }
 
@@ -161,3 +319,141 @@ func (ub UserBookModel) Delete(id int64) error {
 
 	return nil
 }
+
+type UserBookFilters struct {
+	Read       *bool
+	Status     string
+	RatingMin  float32
+	RatingMax  float32
+	ReadAfter  time.Time
+	ReadBefore time.Time
+	Shelf      int64
+	Tags       []string
+}
+
+func ValidateUserBookFilters(v *validator.Validator, f UserBookFilters) {
+	v.Check(f.Shelf >= 0, "shelf", "must be a positive integer")
+	v.Check(len(f.Tags) <= 10, "tags", "must not contain more than 10 tags")
+	v.Check(validator.Unique(f.Tags), "tags", "must not contain duplicate values")
+
+	if f.Status != "" {
+		v.Check(validator.PermittedValue(f.Status, Statuses...), "status", "invalid status value")
+	}
+
+	v.Check(f.RatingMin >= 0, "rating_min", "must not be negative")
+	v.Check(f.RatingMax >= 0, "rating_max", "must not be negative")
+	v.Check(f.RatingMax <= 5, "rating_max", "must not be greater than 5")
+
+	if f.RatingMax != 0 {
+		v.Check(f.RatingMin <= f.RatingMax, "rating_min", "must not be greater than rating_max")
+	}
+
+	if !f.ReadAfter.IsZero() && !f.ReadBefore.IsZero() {
+		v.Check(!f.ReadAfter.After(f.ReadBefore), "read_after", "must not be after read_before")
+	}
+}
+
+func (ub UserBookModel) ListForUser(
+	userID int64,
+	userBookFilters UserBookFilters,
+	filters Filters,
+) ([]*UserBook, Metadata, error) {
+	sortColumn, direction := filters.sortColumn(), filters.sortDirection()
+
+	keyset, keysetArgs := filters.keyset(sortColumn, direction, "id", 12)
+
+	query := fmt.Sprintf(`
+    SELECT %[1]s, id, bookId, userId, read, status, current_page, rating, reviewBody, added_at, read_at, reviewed_at, version,
+        %[2]s::text,`+userBookTagsColumn+`
+    FROM usersBooksRelation
+    LEFT JOIN shelves_books ON shelves_books.userbook_id = usersBooksRelation.id AND shelves_books.shelf_id = $8
+    WHERE userId = $1
+    AND (read = $2 OR $2 IS NULL)
+    AND (status = $3 OR $3 = '')
+    AND (rating >= $4 OR $4 = 0)
+    AND (rating <= $5 OR $5 = 0)
+    AND (read_at >= $6 OR $6 IS NULL)
+    AND (read_at < $7 OR $7 IS NULL)
+    AND (shelves_books.shelf_id IS NOT NULL OR $8 = 0)
+    AND (
+        SELECT count(*) FROM userbooks_tags ut
+        INNER JOIN tags t ON t.id = ut.tag_id
+        WHERE ut.userbook_id = usersBooksRelation.id AND t.name = ANY($11)
+    ) = cardinality($11::text[])
+    AND %[4]s
+    ORDER BY %[2]s %[3]s, id ASC
+    LIMIT $9 OFFSET $10`, filters.countColumn(), sortColumn, direction, keyset)
+
+	if userBookFilters.Tags == nil {
+		userBookFilters.Tags = []string{}
+	}
+
+	args := []any{
+		userID,
+		userBookFilters.Read,
+		userBookFilters.Status,
+		userBookFilters.RatingMin,
+		userBookFilters.RatingMax,
+		nullTime(userBookFilters.ReadAfter),
+		nullTime(userBookFilters.ReadBefore),
+		userBookFilters.Shelf,
+		filters.limit(),
+		filters.offset(),
+		pq.Array(userBookFilters.Tags),
+	}
+	args = append(args, keysetArgs...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := ub.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	userBooks := []*UserBook{}
+	keys := []cursorKey{}
+
+	for rows.Next() {
+		var (
+			userBook UserBook
+			key      cursorKey
+		)
+
+		err := rows.Scan(
+			&totalRecords,
+			&userBook.ID,
+			&userBook.BookID,
+			&userBook.UserID,
+			&userBook.Read,
+			&userBook.Status,
+			&userBook.Page,
+			&userBook.Rating,
+			&userBook.ReviewBody,
+			&userBook.CreatedAt,
+			&userBook.ReadAt,
+			&userBook.ReviewedAt,
+			&userBook.Version,
+			&key.Value,
+			pq.Array(&userBook.Tags),
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		key.ID = userBook.ID
+
+		userBooks = append(userBooks, &userBook)
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	userBooks, metadata := paginate(userBooks, keys, totalRecords, filters)
+
+	return userBooks, metadata, nil
+}
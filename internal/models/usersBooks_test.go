@@ -12,6 +12,7 @@ var validUserBook = UserBook{
 	BookID:     1,
 	UserID:     1,
 	Read:       true,
+	Status:     StatusRead,
 	Rating:     4.5,
 	ReviewBody: "Very good book!",
 	ReadAt:     time.Date(2024, 04, 12, 14, 30, 00, 0, time.UTC),
@@ -32,7 +33,7 @@ func TestUserBookModel_Insert(t *testing.T) {
 		{
 			name: "Want to read",
 			userBook: UserBook{
-				BookID: validUserBook.BookID, UserID: validUserBook.BookID, Read: false, Rating: 0, ReviewBody: "",
+				BookID: validUserBook.BookID, UserID: validUserBook.BookID, Read: false, Status: StatusWantToRead, Rating: 0, ReviewBody: "",
 			},
 			wantErr: "",
 		},
@@ -42,6 +43,7 @@ func TestUserBookModel_Insert(t *testing.T) {
 				BookID:     validUserBook.BookID,
 				UserID:     9,
 				Read:       validUserBook.Read,
+				Status:     validUserBook.Status,
 				Rating:     validUserBook.Rating,
 				ReviewBody: validUserBook.ReviewBody,
 				ReadAt:     validUserBook.ReadAt,
@@ -73,6 +75,7 @@ func TestUserBookModel_Update(t *testing.T) {
 		BookID:     2,
 		UserID:     1,
 		Read:       true,
+		Status:     StatusRead,
 		Rating:     4.5,
 		ReviewBody: "Very good book yes!",
 		ReadAt:     time.Date(2024, 04, 10, 14, 30, 00, 00, time.UTC),
@@ -89,6 +92,7 @@ func TestUserBookModel_Update(t *testing.T) {
 			userBook: UserBook{
 				ID:         originalUserBook.ID,
 				Read:       originalUserBook.Read,
+				Status:     originalUserBook.Status,
 				Rating:     4.25,
 				ReviewBody: "I like this book less now",
 				ReadAt:     originalUserBook.ReadAt,
@@ -101,6 +105,7 @@ func TestUserBookModel_Update(t *testing.T) {
 			userBook: UserBook{
 				ID:         originalUserBook.ID,
 				Read:       originalUserBook.Read,
+				Status:     originalUserBook.Status,
 				Rating:     4.25,
 				ReviewBody: originalUserBook.ReviewBody,
 				ReadAt:     originalUserBook.ReadAt,
@@ -153,7 +158,7 @@ func TestValidateUserBook(t *testing.T) {
 	}{
 		{
 			name:      "Want to read",
-			userBook:  UserBook{UserID: validUserBook.UserID, BookID: validUserBook.BookID},
+			userBook:  UserBook{UserID: validUserBook.UserID, BookID: validUserBook.BookID, Status: StatusWantToRead},
 			wantError: nil,
 		},
 		{
@@ -167,6 +172,7 @@ func TestValidateUserBook(t *testing.T) {
 				UserID:     validUserBook.UserID,
 				BookID:     validUserBook.BookID,
 				Read:       validUserBook.Read,
+				Status:     validUserBook.Status,
 				ReviewBody: "This is the review body.",
 			},
 			wantError: map[string]string{"rating": "if given reviewBody, rating must be provided"},
@@ -177,12 +183,22 @@ func TestValidateUserBook(t *testing.T) {
 				UserID:     validUserBook.UserID,
 				BookID:     validUserBook.BookID,
 				Read:       validUserBook.Read,
+				Status:     validUserBook.Status,
 				ReviewBody: validUserBook.ReviewBody,
 				Rating:     validUserBook.Rating,
 				ReadAt:     time.Now().Add(5 * time.Hour),
 			},
 			wantError: map[string]string{"ReadAt": "must not be in the future"},
 		},
+		{
+			name: "Invalid status",
+			userBook: UserBook{
+				UserID: validUserBook.UserID,
+				BookID: validUserBook.BookID,
+				Status: "finished",
+			},
+			wantError: map[string]string{"status": "invalid status value"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -193,3 +209,104 @@ func TestValidateUserBook(t *testing.T) {
 		})
 	}
 }
+
+func TestUserBook_SetStatus(t *testing.T) {
+	readAt := time.Date(2024, 04, 10, 14, 30, 0, 0, time.UTC)
+
+	t.Run("New entry keeps the given read date", func(t *testing.T) {
+		userBook := UserBook{ReadAt: readAt}
+		userBook.SetStatus(StatusRead)
+
+		assert.Equal(t, userBook.Read, true)
+		assert.Equal(t, userBook.ReadAt, readAt)
+	})
+
+	t.Run("Re-read is finished now", func(t *testing.T) {
+		userBook := UserBook{Status: StatusReading, ReadAt: readAt}
+		userBook.SetStatus(StatusRead)
+
+		assert.Equal(t, userBook.Read, true)
+		assert.Equal(t, userBook.ReadAt.After(readAt), true)
+	})
+
+	t.Run("Staying read keeps the read date", func(t *testing.T) {
+		userBook := UserBook{Status: StatusRead, Read: true, ReadAt: readAt}
+		userBook.SetStatus(StatusRead)
+
+		assert.Equal(t, userBook.ReadAt, readAt)
+	})
+}
+
+func TestUserBookModel_DeleteForUser(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  int64
+		bookID  int64
+		wantErr error
+	}{
+		{name: "Valid Delete", userID: 1, bookID: 2, wantErr: nil},
+		{name: "Book not on shelf", userID: 1, bookID: 1, wantErr: ErrRecordNotFound},
+		{name: "Other user", userID: 2, bookID: 2, wantErr: ErrRecordNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := NewTestDB(t)
+
+			ub := UserBookModel{db}
+
+			err := ub.DeleteForUser(tt.userID, tt.bookID)
+
+			assert.Equal(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateUserBookFilters(t *testing.T) {
+	tests := []struct {
+		name      string
+		filters   UserBookFilters
+		wantError map[string]string
+	}{
+		{
+			name:      "No filters",
+			filters:   UserBookFilters{},
+			wantError: nil,
+		},
+		{
+			name:      "Rating range",
+			filters:   UserBookFilters{RatingMin: 3, RatingMax: 4.5},
+			wantError: nil,
+		},
+		{
+			name:      "Inverted rating range",
+			filters:   UserBookFilters{RatingMin: 4, RatingMax: 2},
+			wantError: map[string]string{"rating_min": "must not be greater than rating_max"},
+		},
+		{
+			name:      "Rating above maximum",
+			filters:   UserBookFilters{RatingMax: 6},
+			wantError: map[string]string{"rating_max": "must not be greater than 5"},
+		},
+		{
+			name: "Inverted date range",
+			filters: UserBookFilters{
+				ReadAfter:  time.Date(2024, 05, 01, 0, 0, 0, 0, time.UTC),
+				ReadBefore: time.Date(2024, 04, 01, 0, 0, 0, 0, time.UTC),
+			},
+			wantError: map[string]string{"read_after": "must not be after read_before"},
+		},
+		{
+			name:      "Duplicate tags",
+			filters:   UserBookFilters{Tags: []string{"favourites", "favourites"}},
+			wantError: map[string]string{"tags": "must not contain duplicate values"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validator.New()
+
+			ValidateUserBookFilters(v, tt.filters)
+			assert.DeepEqual(t, tt.wantError, v.Errors)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	ErrWorkNotFound = errors.New("work not found")
+	ErrSameWork     = errors.New("same work")
+)
+
+// Work groups the editions of a book, like its hardcover, paperback and
+// translations. Ratings and reviews are shared by all editions of a work.
+type Work struct {
+	ID        int64      `json:"id"       example:"5"`
+	Editions  []*Edition `json:"editions"`
+	CreatedAt time.Time  `json:"-"`
+}
+
+// Edition is the summary of a book that is shown next to the other editions
+// of its work, or next to a book it may be a duplicate of.
+type Edition struct {
+	ID        int64  `json:"id"        example:"7"`
+	Title     string `json:"title"     example:"Der Hobbit"`
+	Author    string `json:"author"    example:"J.R.R. Tolkien"`
+	Year      int32  `json:"year"      example:"1957"`
+	Pages     int32  `json:"pages"     example:"384"`
+	ISBN      string `json:"isbn"      example:"9783423213974"`
+	Publisher string `json:"publisher" example:"dtv"`
+	Language  string `json:"language"  example:"de"`
+}
+
+// editionColumns are the columns scanned by scanEditionDest, in the same order.
+const editionColumns = `id, title, author, year, pages, COALESCE(isbn, ''), publisher, language`
+
+func scanEditionDest(edition *Edition) []any {
+	return []any{
+		&edition.ID,
+		&edition.Title,
+		&edition.Author,
+		&edition.Year,
+		&edition.Pages,
+		&edition.ISBN,
+		&edition.Publisher,
+		&edition.Language,
+	}
+}
+
+type WorkModel struct {
+	DB *sql.DB
+}
+
+func (m WorkModel) Get(id int64) (*Work, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+    SELECT id, created_at
+    FROM works
+    WHERE id = $1`
+
+	var work Work
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, id).Scan(&work.ID, &work.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	work.Editions, err = m.Editions(work.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &work, nil
+}
+
+// Editions lists the editions of a work, oldest first. The book with the id
+// exceptBookID is left out, so a book can list its siblings.
+func (m WorkModel) Editions(workID, exceptBookID int64) ([]*Edition, error) {
+	query := `
+    SELECT ` + editionColumns + `
+    FROM books
+    WHERE work_id = $1 AND id <> $2 AND deleted_at IS NULL
+    ORDER BY year ASC, id ASC`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, workID, exceptBookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	editions := []*Edition{}
+
+	for rows.Next() {
+		var edition Edition
+
+		err := rows.Scan(scanEditionDest(&edition)...)
+		if err != nil {
+			return nil, err
+		}
+
+		editions = append(editions, &edition)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return editions, nil
+}
+
+// Merge moves the book and all other editions of its work into the work of
+// the book intoBookID, and returns the id of that work. The emptied work is
+// removed by the books_work_cleanup trigger.
+func (m WorkModel) Merge(bookID, intoBookID int64) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `
+    SELECT work_id
+    FROM books
+    WHERE id = $1
+    FOR UPDATE`
+
+	var fromWorkID, intoWorkID int64
+
+	err = tx.QueryRowContext(ctx, query, bookID).Scan(&fromWorkID)
+	if err == nil {
+		err = tx.QueryRowContext(ctx, query, intoBookID).Scan(&intoWorkID)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrRecordNotFound
+		}
+		return 0, err
+	}
+
+	if fromWorkID == intoWorkID {
+		return 0, ErrSameWork
+	}
+
+	query = `
+    UPDATE books
+    SET work_id = $1, version = version + 1
+    WHERE work_id = $2`
+
+	_, err = tx.ExecContext(ctx, query, intoWorkID, fromWorkID)
+	if err != nil {
+		return 0, err
+	}
+
+	return intoWorkID, tx.Commit()
+}
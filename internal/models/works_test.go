@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/svenrisse/bookshelf/internal/assert"
+)
+
+func TestWorkModel_Merge(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := WorkModel{db}
+
+	workID, err := m.Merge(1, 2)
+	assert.NilError(t, err)
+	assert.Equal(t, workID, int64(2))
+
+	work, err := m.Get(workID)
+	assert.NilError(t, err)
+	assert.Equal(t, len(work.Editions), 2)
+
+	_, err = m.Get(1)
+	assert.Equal(t, err, ErrRecordNotFound)
+
+	_, err = m.Merge(2, 1)
+	assert.Equal(t, err, ErrSameWork)
+
+	_, err = m.Merge(1, 99)
+	assert.Equal(t, err, ErrRecordNotFound)
+}
+
+func TestWorkModel_Editions(t *testing.T) {
+	db := NewTestDB(t)
+
+	m := WorkModel{db}
+
+	editions, err := m.Editions(1, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, len(editions), 1)
+	assert.Equal(t, editions[0].ISBN, "9780261103573")
+
+	editions, err = m.Editions(1, 1)
+	assert.NilError(t, err)
+	assert.Equal(t, len(editions), 0)
+}
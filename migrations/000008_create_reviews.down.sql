@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS review_helpful_votes_count ON review_helpful_votes;
+DROP TRIGGER IF EXISTS usersbooksrelation_rating_stats ON usersBooksRelation;
+DROP FUNCTION IF EXISTS review_helpful_votes_count();
+DROP FUNCTION IF EXISTS usersbooksrelation_rating_stats();
+DROP FUNCTION IF EXISTS book_rating_stats_apply(bigint, real, integer);
+DROP TABLE IF EXISTS book_rating_stats;
+DROP TABLE IF EXISTS review_helpful_votes;
+DROP INDEX IF EXISTS usersbooksrelation_bookid_reviewed_at_idx;
+ALTER TABLE usersBooksRelation DROP COLUMN IF EXISTS helpful_count;
@@ -0,0 +1,90 @@
+ALTER TABLE usersBooksRelation ADD COLUMN IF NOT EXISTS helpful_count integer NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS usersbooksrelation_bookid_reviewed_at_idx ON usersBooksRelation (bookId, reviewed_at DESC)
+WHERE reviewBody <> '';
+
+CREATE TABLE IF NOT EXISTS review_helpful_votes (
+    review_id bigint NOT NULL REFERENCES usersBooksRelation ON DELETE CASCADE,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (review_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS book_rating_stats (
+    book_id bigint PRIMARY KEY REFERENCES books ON DELETE CASCADE,
+    ratings_count integer NOT NULL DEFAULT 0,
+    ratings_sum double precision NOT NULL DEFAULT 0,
+    histogram integer[] NOT NULL DEFAULT '{0,0,0,0,0}'
+);
+
+-- book_rating_stats_apply adds (sign = 1) or removes (sign = -1) a single
+-- rating from the aggregates of a book. Unrated entries are stored as 0.
+CREATE OR REPLACE FUNCTION book_rating_stats_apply(book bigint, rating real, sign integer) RETURNS void AS $$
+DECLARE
+    star integer;
+BEGIN
+    IF rating IS NULL OR rating <= 0 THEN
+        RETURN;
+    END IF;
+
+    star := LEAST(5, GREATEST(1, round(rating::numeric)::integer));
+
+    INSERT INTO book_rating_stats (book_id) VALUES (book) ON CONFLICT (book_id) DO NOTHING;
+
+    UPDATE book_rating_stats
+    SET ratings_count = ratings_count + sign,
+        ratings_sum = ratings_sum + sign * rating,
+        histogram[star] = histogram[star] + sign
+    WHERE book_id = book;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION usersbooksrelation_rating_stats() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM book_rating_stats_apply(OLD.bookId, OLD.rating, -1);
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM book_rating_stats_apply(NEW.bookId, NEW.rating, 1);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER usersbooksrelation_rating_stats
+AFTER INSERT OR DELETE OR UPDATE OF rating, bookId ON usersBooksRelation
+FOR EACH ROW EXECUTE FUNCTION usersbooksrelation_rating_stats();
+
+CREATE OR REPLACE FUNCTION review_helpful_votes_count() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE usersBooksRelation SET helpful_count = helpful_count + 1 WHERE id = NEW.review_id;
+    ELSE
+        UPDATE usersBooksRelation SET helpful_count = helpful_count - 1 WHERE id = OLD.review_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER review_helpful_votes_count
+AFTER INSERT OR DELETE ON review_helpful_votes
+FOR EACH ROW EXECUTE FUNCTION review_helpful_votes_count();
+
+INSERT INTO book_rating_stats (book_id, ratings_count, ratings_sum, histogram)
+SELECT
+    bookId,
+    count(*),
+    sum(rating),
+    ARRAY[
+        count(*) FILTER (WHERE round(rating::numeric) <= 1),
+        count(*) FILTER (WHERE round(rating::numeric) = 2),
+        count(*) FILTER (WHERE round(rating::numeric) = 3),
+        count(*) FILTER (WHERE round(rating::numeric) = 4),
+        count(*) FILTER (WHERE round(rating::numeric) >= 5)
+    ]
+FROM usersBooksRelation
+WHERE rating > 0
+GROUP BY bookId;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS reading_progress;
+ALTER TABLE usersBooksRelation DROP CONSTRAINT IF EXISTS usersbooksrelation_current_page_check;
+ALTER TABLE usersBooksRelation DROP CONSTRAINT IF EXISTS usersbooksrelation_status_check;
+ALTER TABLE usersBooksRelation DROP COLUMN IF EXISTS current_page;
+ALTER TABLE usersBooksRelation DROP COLUMN IF EXISTS status;
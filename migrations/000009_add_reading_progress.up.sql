@@ -0,0 +1,18 @@
+ALTER TABLE usersBooksRelation ADD COLUMN IF NOT EXISTS status text NOT NULL DEFAULT 'want-to-read';
+ALTER TABLE usersBooksRelation ADD COLUMN IF NOT EXISTS current_page integer NOT NULL DEFAULT 0;
+
+UPDATE usersBooksRelation SET status = 'read' WHERE read;
+
+ALTER TABLE usersBooksRelation ADD CONSTRAINT usersbooksrelation_status_check
+CHECK (status IN ('want-to-read', 'reading', 'read', 'abandoned'));
+ALTER TABLE usersBooksRelation ADD CONSTRAINT usersbooksrelation_current_page_check CHECK (current_page >= 0);
+
+CREATE TABLE IF NOT EXISTS reading_progress (
+    id bigserial PRIMARY KEY,
+    userbook_id bigint NOT NULL REFERENCES usersBooksRelation ON DELETE CASCADE,
+    page integer NOT NULL CHECK (page >= 0),
+    percentage real NOT NULL CHECK (percentage BETWEEN 0 AND 100),
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS reading_progress_userbook_id_idx ON reading_progress (userbook_id, created_at);
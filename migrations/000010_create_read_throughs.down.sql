@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS usersbooksrelation_read_throughs ON usersBooksRelation;
+DROP FUNCTION IF EXISTS usersbooksrelation_read_throughs();
+DROP TABLE IF EXISTS read_throughs;
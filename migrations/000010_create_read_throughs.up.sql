@@ -0,0 +1,70 @@
+CREATE TABLE IF NOT EXISTS read_throughs (
+    id bigserial PRIMARY KEY,
+    userbook_id bigint NOT NULL REFERENCES usersBooksRelation ON DELETE CASCADE,
+    started_at timestamp(0) with time zone,
+    finished_at timestamp(0) with time zone,
+    rating real NOT NULL DEFAULT 0 CHECK (rating BETWEEN 0 AND 5),
+    notes text NOT NULL DEFAULT '',
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS read_throughs_userbook_id_idx ON read_throughs (userbook_id);
+CREATE INDEX IF NOT EXISTS read_throughs_finished_at_idx ON read_throughs (finished_at);
+
+-- usersbooksrelation_read_throughs opens a read-through when a book is
+-- started and finishes the open one (or records a new one) when it is read.
+CREATE OR REPLACE FUNCTION usersbooksrelation_read_throughs() RETURNS trigger AS $$
+DECLARE
+    finished timestamp(0) with time zone;
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.status = OLD.status THEN
+        RETURN NULL;
+    END IF;
+
+    IF NEW.status = 'reading' THEN
+        INSERT INTO read_throughs (userbook_id, started_at)
+        SELECT NEW.id, NOW()
+        WHERE NOT EXISTS (SELECT 1 FROM read_throughs WHERE userbook_id = NEW.id AND finished_at IS NULL);
+    ELSIF NEW.status = 'read' THEN
+        finished := CASE WHEN NEW.read_at >= '1900-01-01' THEN NEW.read_at ELSE NOW() END;
+
+        UPDATE read_throughs
+        SET finished_at = finished, rating = COALESCE(NEW.rating, 0), version = version + 1
+        WHERE id = (
+            SELECT id FROM read_throughs
+            WHERE userbook_id = NEW.id AND finished_at IS NULL
+            ORDER BY started_at DESC NULLS LAST, id DESC
+            LIMIT 1
+        );
+
+        IF NOT FOUND THEN
+            INSERT INTO read_throughs (userbook_id, finished_at, rating)
+            VALUES (NEW.id, finished, COALESCE(NEW.rating, 0));
+        END IF;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER usersbooksrelation_read_throughs
+AFTER INSERT OR UPDATE OF status ON usersBooksRelation
+FOR EACH ROW EXECUTE FUNCTION usersbooksrelation_read_throughs();
+
+-- Existing entries become their first read-through: a finished one for read
+-- entries and an open one for entries being read. Entries that were marked as
+-- read without a date fall back to the date they were added.
+INSERT INTO read_throughs (userbook_id, finished_at, rating)
+SELECT
+    id,
+    CASE WHEN read_at >= '1900-01-01' THEN read_at ELSE added_at END,
+    COALESCE(rating, 0)
+FROM usersBooksRelation
+WHERE status = 'read';
+
+INSERT INTO read_throughs (userbook_id, started_at)
+SELECT ub.id, (SELECT min(created_at) FROM reading_progress WHERE userbook_id = ub.id)
+FROM usersBooksRelation ub
+WHERE ub.status = 'reading'
+AND NOT EXISTS (SELECT 1 FROM read_throughs rt WHERE rt.userbook_id = ub.id);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS shelves (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    name text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1,
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS shelves_books (
+    shelf_id bigint NOT NULL REFERENCES shelves ON DELETE CASCADE,
+    userbook_id bigint NOT NULL REFERENCES usersBooksRelation ON DELETE CASCADE,
+    position integer NOT NULL DEFAULT 0 CHECK (position >= 0),
+    shelved_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (shelf_id, userbook_id)
+);
+
+CREATE INDEX IF NOT EXISTS shelves_books_userbook_id_idx ON shelves_books (userbook_id);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS goals (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    year integer NOT NULL,
+    target_books integer NOT NULL DEFAULT 0 CHECK (target_books >= 0),
+    target_pages integer NOT NULL DEFAULT 0 CHECK (target_pages >= 0),
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1,
+    UNIQUE (user_id, year),
+    CONSTRAINT goals_target_check CHECK (target_books > 0 OR target_pages > 0)
+);
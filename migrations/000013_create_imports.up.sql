@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS imports (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    file_hash bytea NOT NULL,
+    source text NOT NULL,
+    status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    total_rows integer NOT NULL DEFAULT 0,
+    processed integer NOT NULL DEFAULT 0,
+    matched integer NOT NULL DEFAULT 0,
+    created integer NOT NULL DEFAULT 0,
+    failed integer NOT NULL DEFAULT 0,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    finished_at timestamp(0) with time zone,
+    UNIQUE (user_id, file_hash)
+);
+
+CREATE TABLE IF NOT EXISTS import_failures (
+    import_id bigint NOT NULL REFERENCES imports ON DELETE CASCADE,
+    row_number integer NOT NULL,
+    title text NOT NULL DEFAULT '',
+    reason text NOT NULL,
+    PRIMARY KEY (import_id, row_number)
+);
@@ -0,0 +1,9 @@
+ALTER TABLE books DROP CONSTRAINT IF EXISTS books_language_check;
+ALTER TABLE books DROP CONSTRAINT IF EXISTS books_isbn_check;
+ALTER TABLE books DROP CONSTRAINT IF EXISTS books_isbn_key;
+ALTER TABLE books DROP COLUMN IF EXISTS original_title;
+ALTER TABLE books DROP COLUMN IF EXISTS cover_url;
+ALTER TABLE books DROP COLUMN IF EXISTS description;
+ALTER TABLE books DROP COLUMN IF EXISTS language;
+ALTER TABLE books DROP COLUMN IF EXISTS publisher;
+ALTER TABLE books DROP COLUMN IF EXISTS isbn;
@@ -0,0 +1,10 @@
+ALTER TABLE books ADD COLUMN IF NOT EXISTS isbn text;
+ALTER TABLE books ADD COLUMN IF NOT EXISTS publisher text NOT NULL DEFAULT '';
+ALTER TABLE books ADD COLUMN IF NOT EXISTS language text NOT NULL DEFAULT '';
+ALTER TABLE books ADD COLUMN IF NOT EXISTS description text NOT NULL DEFAULT '';
+ALTER TABLE books ADD COLUMN IF NOT EXISTS cover_url text NOT NULL DEFAULT '';
+ALTER TABLE books ADD COLUMN IF NOT EXISTS original_title text NOT NULL DEFAULT '';
+
+ALTER TABLE books ADD CONSTRAINT books_isbn_key UNIQUE (isbn);
+ALTER TABLE books ADD CONSTRAINT books_isbn_check CHECK (isbn ~ '^97[89][0-9]{10}$');
+ALTER TABLE books ADD CONSTRAINT books_language_check CHECK (language ~ '^([a-z]{2,3})?$');
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS books_authors;
+DROP TABLE IF EXISTS authors;
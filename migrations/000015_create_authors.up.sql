@@ -0,0 +1,41 @@
+-- name_key folds punctuation, spacing and case, so that "J.R.R. Tolkien" and
+-- "J. R. R. Tolkien" are the same author.
+CREATE TABLE IF NOT EXISTS authors (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    name_key text GENERATED ALWAYS AS (lower(regexp_replace(name, '[^[:alnum:]]+', '', 'g'))) STORED,
+    bio text NOT NULL DEFAULT '',
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1,
+    CONSTRAINT authors_name_key_key UNIQUE (name_key)
+);
+
+CREATE TABLE IF NOT EXISTS books_authors (
+    book_id bigint NOT NULL REFERENCES books ON DELETE CASCADE,
+    author_id bigint NOT NULL REFERENCES authors ON DELETE CASCADE,
+    role text NOT NULL DEFAULT 'author' CHECK (role IN ('author', 'translator', 'editor', 'illustrator')),
+    position integer NOT NULL DEFAULT 0,
+    PRIMARY KEY (book_id, author_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS books_authors_author_id_idx ON books_authors (author_id);
+
+-- Split the existing bylines on "&", ";" and " and ", the same way
+-- models.SplitByline does.
+INSERT INTO authors (name)
+SELECT DISTINCT ON (lower(regexp_replace(name, '[^[:alnum:]]+', '', 'g'))) name
+FROM (
+    SELECT btrim(regexp_split_to_table(author, '\s*(;|&|\sand\s)\s*')) AS name
+    FROM books
+) names
+WHERE regexp_replace(name, '[^[:alnum:]]+', '', 'g') <> ''
+ORDER BY lower(regexp_replace(name, '[^[:alnum:]]+', '', 'g')), name
+ON CONFLICT (name_key) DO NOTHING;
+
+INSERT INTO books_authors (book_id, author_id, role, position)
+SELECT b.id, a.id, 'author', min(n.position) - 1
+FROM books b
+CROSS JOIN LATERAL regexp_split_to_table(b.author, '\s*(;|&|\sand\s)\s*') WITH ORDINALITY AS n(name, position)
+INNER JOIN authors a ON a.name_key = lower(regexp_replace(n.name, '[^[:alnum:]]+', '', 'g'))
+GROUP BY b.id, a.id
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS series (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS series_books (
+    series_id bigint NOT NULL REFERENCES series ON DELETE CASCADE,
+    book_id bigint NOT NULL REFERENCES books ON DELETE CASCADE,
+    position numeric(6, 2) NOT NULL CHECK (position >= 0),
+    PRIMARY KEY (series_id, book_id)
+);
+
+CREATE INDEX IF NOT EXISTS series_books_book_id_idx ON series_books (book_id);
+CREATE INDEX IF NOT EXISTS series_books_position_idx ON series_books (series_id, position);
@@ -0,0 +1,7 @@
+DELETE FROM permissions WHERE code = 'books:admin';
+DROP TRIGGER IF EXISTS books_work_cleanup ON books;
+DROP TRIGGER IF EXISTS books_work ON books;
+DROP FUNCTION IF EXISTS books_work_cleanup();
+DROP FUNCTION IF EXISTS books_work();
+ALTER TABLE books DROP COLUMN IF EXISTS work_id;
+DROP TABLE IF EXISTS works;
@@ -0,0 +1,50 @@
+CREATE TABLE IF NOT EXISTS works (
+    id bigserial PRIMARY KEY,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE books ADD COLUMN IF NOT EXISTS work_id bigint REFERENCES works;
+
+-- Every existing book starts out as the only edition of its own work.
+INSERT INTO works (id, created_at)
+SELECT id, created_at FROM books;
+
+SELECT setval('works_id_seq', (SELECT COALESCE(max(id), 0) + 1 FROM works), false);
+
+UPDATE books SET work_id = id;
+
+ALTER TABLE books ALTER COLUMN work_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS books_work_id_idx ON books (work_id);
+
+-- books_work gives a new book its own work unless it was added as an edition
+-- of an existing one.
+CREATE OR REPLACE FUNCTION books_work() RETURNS trigger AS $$
+BEGIN
+    IF NEW.work_id IS NULL THEN
+        INSERT INTO works DEFAULT VALUES RETURNING id INTO NEW.work_id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER books_work
+BEFORE INSERT ON books
+FOR EACH ROW EXECUTE FUNCTION books_work();
+
+-- books_work_cleanup removes works that lost their last edition.
+CREATE OR REPLACE FUNCTION books_work_cleanup() RETURNS trigger AS $$
+BEGIN
+    DELETE FROM works
+    WHERE id = OLD.work_id AND NOT EXISTS (SELECT 1 FROM books WHERE work_id = OLD.work_id);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER books_work_cleanup
+AFTER DELETE OR UPDATE OF work_id ON books
+FOR EACH ROW EXECUTE FUNCTION books_work_cleanup();
+
+INSERT INTO permissions (code) VALUES ('books:admin') ON CONFLICT (code) DO NOTHING;
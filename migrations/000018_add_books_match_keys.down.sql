@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS books_title_key_author_key_idx;
+ALTER TABLE books DROP COLUMN IF EXISTS author_key;
+ALTER TABLE books DROP COLUMN IF EXISTS title_key;
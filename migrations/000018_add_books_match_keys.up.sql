@@ -0,0 +1,8 @@
+-- title_key and author_key fold punctuation, spacing and case the same way as
+-- authors.name_key, so that "The Hobbit" and "the hobbit." match.
+ALTER TABLE books ADD COLUMN IF NOT EXISTS title_key text
+    GENERATED ALWAYS AS (lower(regexp_replace(title, '[^[:alnum:]]+', '', 'g'))) STORED;
+ALTER TABLE books ADD COLUMN IF NOT EXISTS author_key text
+    GENERATED ALWAYS AS (lower(regexp_replace(author, '[^[:alnum:]]+', '', 'g'))) STORED;
+
+CREATE INDEX IF NOT EXISTS books_title_key_author_key_idx ON books (title_key, author_key);
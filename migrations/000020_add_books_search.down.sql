@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS books_search_idx;
+CREATE INDEX IF NOT EXISTS books_title_idx ON books USING GIN (to_tsvector('simple', title));
+ALTER TABLE books DROP COLUMN IF EXISTS search;
+DROP FUNCTION IF EXISTS books_search_vector(text, text, text[], text);
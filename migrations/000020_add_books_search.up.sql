@@ -0,0 +1,17 @@
+-- books_search_vector weighs title over author over genres over description.
+-- array_to_string is only stable, so the function is declared immutable itself
+-- to be usable in a generated column.
+CREATE OR REPLACE FUNCTION books_search_vector(title text, author text, genres text[], description text)
+RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('simple', title), 'A')
+        || setweight(to_tsvector('simple', author), 'B')
+        || setweight(to_tsvector('simple', array_to_string(genres, ' ')), 'C')
+        || setweight(to_tsvector('simple', description), 'D')
+$$ LANGUAGE sql IMMUTABLE;
+
+ALTER TABLE books ADD COLUMN IF NOT EXISTS search tsvector
+    GENERATED ALWAYS AS (books_search_vector(title, author, genres, description)) STORED;
+
+DROP INDEX IF EXISTS books_title_idx;
+
+CREATE INDEX IF NOT EXISTS books_search_idx ON books USING GIN (search);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS books_pages_idx;
+DROP INDEX IF EXISTS books_year_idx;
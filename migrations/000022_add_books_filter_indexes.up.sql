@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS books_year_idx ON books (year) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS books_pages_idx ON books (pages) WHERE deleted_at IS NULL;
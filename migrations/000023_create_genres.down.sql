@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS genre_subtree(text[]);
+DROP TABLE IF EXISTS genre_aliases;
+DROP TABLE IF EXISTS genres;
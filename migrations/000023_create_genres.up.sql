@@ -0,0 +1,75 @@
+-- name_key folds punctuation, spacing and case the same way authors do, so
+-- that "Sci-Fi" and "SciFi" are one genre.
+CREATE TABLE IF NOT EXISTS genres (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    name_key text GENERATED ALWAYS AS (lower(regexp_replace(name, '[^[:alnum:]]+', '', 'g'))) STORED,
+    parent_id bigint REFERENCES genres ON DELETE SET NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1,
+    CONSTRAINT genres_name_key_key UNIQUE (name_key),
+    CONSTRAINT genres_parent_id_check CHECK (parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS genres_parent_id_idx ON genres (parent_id);
+
+-- genre_aliases are other spellings of a genre, like "SF" for "Science
+-- Fiction". Books are always stored with the canonical name.
+CREATE TABLE IF NOT EXISTS genre_aliases (
+    alias text NOT NULL,
+    alias_key text GENERATED ALWAYS AS (lower(regexp_replace(alias, '[^[:alnum:]]+', '', 'g'))) STORED,
+    genre_id bigint NOT NULL REFERENCES genres ON DELETE CASCADE,
+    CONSTRAINT genre_aliases_alias_key_key UNIQUE (alias_key)
+);
+
+CREATE INDEX IF NOT EXISTS genre_aliases_genre_id_idx ON genre_aliases (genre_id);
+
+-- genre_subtree returns the given genres together with all genres below them.
+-- Names that aren't genres are kept as they are.
+CREATE OR REPLACE FUNCTION genre_subtree(names text[]) RETURNS text[] AS $$
+    WITH RECURSIVE tree AS (
+        SELECT id, name
+        FROM genres
+        WHERE name_key IN (SELECT lower(regexp_replace(n, '[^[:alnum:]]+', '', 'g')) FROM unnest(names) n)
+        UNION
+        SELECT g.id, g.name
+        FROM genres g
+        INNER JOIN tree t ON g.parent_id = t.id
+    )
+    SELECT names || COALESCE(array_agg(name), '{}') FROM tree
+$$ LANGUAGE sql STABLE;
+
+-- Every genre in use becomes part of the vocabulary, spelled the way most
+-- books spell it. The genre given to imported books must always exist.
+INSERT INTO genres (name)
+SELECT name
+FROM (
+    SELECT DISTINCT ON (lower(regexp_replace(name, '[^[:alnum:]]+', '', 'g'))) name
+    FROM (
+        SELECT btrim(name) AS name, count(*) AS books
+        FROM books, unnest(genres) name
+        GROUP BY btrim(name)
+    ) spellings
+    WHERE regexp_replace(name, '[^[:alnum:]]+', '', 'g') <> ''
+    ORDER BY lower(regexp_replace(name, '[^[:alnum:]]+', '', 'g')), books DESC, name
+) names
+ON CONFLICT (name_key) DO NOTHING;
+
+INSERT INTO genres (name) VALUES ('Uncategorized') ON CONFLICT (name_key) DO NOTHING;
+
+-- Rewrite the genres of every book to their canonical names, keeping their
+-- order and dropping the duplicates that folding may produce.
+UPDATE books b
+SET genres = canonical.genres, version = version + 1
+FROM (
+    SELECT b.id, array_agg(n.name ORDER BY n.position) AS genres
+    FROM books b
+    CROSS JOIN LATERAL (
+        SELECT DISTINCT ON (g.id) g.id, g.name, u.position
+        FROM unnest(b.genres) WITH ORDINALITY AS u(name, position)
+        INNER JOIN genres g ON g.name_key = lower(regexp_replace(u.name, '[^[:alnum:]]+', '', 'g'))
+        ORDER BY g.id, u.position
+    ) n
+    GROUP BY b.id
+) canonical
+WHERE b.id = canonical.id AND b.genres <> canonical.genres;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS tags (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    name text NOT NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1,
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS userbooks_tags (
+    userbook_id bigint NOT NULL REFERENCES usersBooksRelation ON DELETE CASCADE,
+    tag_id bigint NOT NULL REFERENCES tags ON DELETE CASCADE,
+    PRIMARY KEY (userbook_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS userbooks_tags_tag_id_idx ON userbooks_tags (tag_id);
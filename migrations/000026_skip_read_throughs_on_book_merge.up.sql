@@ -0,0 +1,57 @@
+-- usersbooksrelation_read_throughs opens a read-through when a book is
+-- started and finishes the open one (or records a new one) when it is read.
+-- A re-read doesn't take over the read_at and rating of the pass before it:
+-- unless they are changed along with the status, it finishes now and unrated.
+CREATE OR REPLACE FUNCTION usersbooksrelation_read_throughs() RETURNS trigger AS $$
+DECLARE
+    reread boolean;
+    finished timestamp(0) with time zone;
+    finished_rating real;
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.status = OLD.status THEN
+        RETURN NULL;
+    END IF;
+
+    -- Merging two books moves the read-throughs of the duplicate entry along
+    -- with its status, so they must not be recorded a second time.
+    IF current_setting('bookshelf.merging_books', true) = 'on' THEN
+        RETURN NULL;
+    END IF;
+
+    IF NEW.status = 'reading' THEN
+        INSERT INTO read_throughs (userbook_id, started_at)
+        SELECT NEW.id, NOW()
+        WHERE NOT EXISTS (SELECT 1 FROM read_throughs WHERE userbook_id = NEW.id AND finished_at IS NULL);
+    ELSIF NEW.status = 'read' THEN
+        reread := TG_OP = 'UPDATE' AND EXISTS (
+            SELECT 1 FROM read_throughs WHERE userbook_id = NEW.id AND finished_at IS NOT NULL
+        );
+
+        finished := CASE
+            WHEN reread AND NEW.read_at IS NOT DISTINCT FROM OLD.read_at THEN NOW()
+            WHEN NEW.read_at >= '1900-01-01' THEN NEW.read_at
+            ELSE NOW()
+        END;
+        finished_rating := CASE
+            WHEN reread AND NEW.rating IS NOT DISTINCT FROM OLD.rating THEN 0
+            ELSE COALESCE(NEW.rating, 0)
+        END;
+
+        UPDATE read_throughs
+        SET finished_at = finished, rating = finished_rating, version = version + 1
+        WHERE id = (
+            SELECT id FROM read_throughs
+            WHERE userbook_id = NEW.id AND finished_at IS NULL
+            ORDER BY started_at DESC NULLS LAST, id DESC
+            LIMIT 1
+        );
+
+        IF NOT FOUND THEN
+            INSERT INTO read_throughs (userbook_id, finished_at, rating)
+            VALUES (NEW.id, finished, finished_rating);
+        END IF;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS books_isbn_key;
+
+ALTER TABLE books ADD CONSTRAINT books_isbn_key UNIQUE (isbn);
@@ -0,0 +1,5 @@
+-- Deleted books give up their ISBN, so the book can be added again. Restoring
+-- a deleted book fails while another book has its ISBN.
+ALTER TABLE books DROP CONSTRAINT IF EXISTS books_isbn_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS books_isbn_key ON books (isbn) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- updated_at tells a running import from one that was abandoned, for example
+-- by a restart of the server, so the file can be imported again.
+ALTER TABLE imports ADD COLUMN IF NOT EXISTS updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW();